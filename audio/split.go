@@ -0,0 +1,158 @@
+package audio
+
+import (
+	"encoding/binary"
+	"errors"
+	"math"
+	"time"
+)
+
+// Frames returns the number of frames in the audio, each holding a sample for every channel.
+func (w *WAV) Frames() int {
+	blockAlign := w.Format.BlockAlign()
+	if blockAlign == 0 {
+		return 0
+	}
+	return len(w.Data) / blockAlign
+}
+
+// Slice returns the audio between start and end, rounded to the nearest frames. The
+// returned WAV shares its data with w.
+func (w *WAV) Slice(start, end time.Duration) *WAV {
+	return w.sliceFrames(w.frameAt(start), w.frameAt(end))
+}
+
+// sliceFrames returns the frames [first, last) of the audio.
+func (w *WAV) sliceFrames(first, last int) *WAV {
+	last = max(last, first)
+	blockAlign := w.Format.BlockAlign()
+	return &WAV{Format: w.Format, Data: w.Data[first*blockAlign : last*blockAlign]}
+}
+
+// frameAt returns the index of the frame nearest to d, clamped to the audio.
+func (w *WAV) frameAt(d time.Duration) int {
+	frame := int((int64(d)*int64(w.Format.SampleRate) + int64(time.Second)/2) / int64(time.Second))
+	return min(max(frame, 0), w.Frames())
+}
+
+// frameTime returns the position of the frame with the given index.
+func (w *WAV) frameTime(frame int) time.Duration {
+	return time.Duration(int64(frame) * int64(time.Second) / int64(w.Format.SampleRate))
+}
+
+// Window is a section of audio returned by Split.
+type Window struct {
+	Start time.Duration // Position of the window in the original audio
+	End   time.Duration
+	WAV   *WAV
+}
+
+// SplitOptions controls how Split divides audio into windows.
+type SplitOptions struct {
+	MaxDuration time.Duration // Maximum length of each window; required
+	Overlap     time.Duration // Audio shared by consecutive windows
+	// SearchRange is how far before MaxDuration to look for silence to end a window at.
+	// It defaults to a fifth of MaxDuration.
+	SearchRange time.Duration
+}
+
+// analysisBlock is the length of the blocks whose energy is compared when looking for silence.
+const analysisBlock = 20 * time.Millisecond
+
+// Split divides w into windows of at most opts.MaxDuration, each starting opts.Overlap
+// before the end of the previous one. Windows end at the quietest point within
+// opts.SearchRange of their maximum length, so that cuts fall in pauses between words
+// where possible. Audio whose samples cannot be decoded is cut at the maximum length.
+func Split(w *WAV, opts SplitOptions) ([]Window, error) {
+	if opts.MaxDuration <= 0 {
+		return nil, errors.New("audio: MaxDuration must be positive")
+	}
+	if w.Format.SampleRate == 0 || w.Format.BlockAlign() == 0 {
+		return nil, errors.New("audio: invalid format")
+	}
+	searchRange := opts.SearchRange
+	if searchRange <= 0 {
+		searchRange = opts.MaxDuration / 5
+	}
+	if opts.Overlap < 0 || opts.Overlap+searchRange >= opts.MaxDuration {
+		return nil, errors.New("audio: Overlap and SearchRange must add up to less than MaxDuration")
+	}
+
+	var (
+		windows []Window
+		total   = w.Frames()
+		start   = 0
+	)
+	for {
+		end := min(start+w.frameAt(opts.MaxDuration), total)
+		if end < total {
+			end = w.quietestFrame(max(end-w.frameAt(searchRange), start+1), end)
+		}
+		windows = append(windows, Window{
+			Start: w.frameTime(start),
+			End:   w.frameTime(end),
+			WAV:   w.sliceFrames(start, end),
+		})
+		if end >= total {
+			return windows, nil
+		}
+		start = max(end-w.frameAt(opts.Overlap), start+1)
+	}
+}
+
+// quietestFrame returns the centre of the block with the least energy between the frames
+// from and to, or to if the samples cannot be decoded.
+func (w *WAV) quietestFrame(from, to int) int {
+	if _, ok := w.sample(0); !ok {
+		return to
+	}
+
+	blockFrames := max(w.frameAt(analysisBlock), 1)
+	best, bestEnergy := to, math.Inf(1)
+	// Walk backwards so that ties are resolved in favour of longer windows.
+	for blockEnd := to; blockEnd-blockFrames >= from; blockEnd -= blockFrames {
+		if energy := w.energy(blockEnd-blockFrames, blockEnd); energy < bestEnergy {
+			best, bestEnergy = blockEnd-blockFrames/2, energy
+		}
+	}
+	return best
+}
+
+// energy returns the mean square of the samples in the frames [from, to).
+func (w *WAV) energy(from, to int) float64 {
+	var sum float64
+	channels := int(w.Format.Channels)
+	for i := from * channels; i < to*channels; i++ {
+		s, _ := w.sample(i)
+		sum += s * s
+	}
+	return sum / float64((to-from)*channels)
+}
+
+// sample returns the sample with index i, scaled to [-1, 1], and whether the format is
+// supported.
+func (w *WAV) sample(i int) (float64, bool) {
+	bytesPerSample := int(w.Format.BitsPerSample) / 8
+	offset := i * bytesPerSample
+	if offset+bytesPerSample > len(w.Data) {
+		return 0, false
+	}
+	b := w.Data[offset : offset+bytesPerSample]
+
+	switch {
+	case w.Format.AudioFormat == FormatPCM && bytesPerSample == 1:
+		return (float64(b[0]) - 128) / 128, true
+	case w.Format.AudioFormat == FormatPCM && bytesPerSample == 2:
+		return float64(int16(binary.LittleEndian.Uint16(b))) / (1 << 15), true
+	case w.Format.AudioFormat == FormatPCM && bytesPerSample == 3:
+		v := int32(b[0]) | int32(b[1])<<8 | int32(int8(b[2]))<<16
+		return float64(v) / (1 << 23), true
+	case w.Format.AudioFormat == FormatPCM && bytesPerSample == 4:
+		return float64(int32(binary.LittleEndian.Uint32(b))) / (1 << 31), true
+	case w.Format.AudioFormat == FormatIEEEFloat && bytesPerSample == 4:
+		return float64(math.Float32frombits(binary.LittleEndian.Uint32(b))), true
+	case w.Format.AudioFormat == FormatIEEEFloat && bytesPerSample == 8:
+		return math.Float64frombits(binary.LittleEndian.Uint64(b)), true
+	}
+	return 0, false
+}
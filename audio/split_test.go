@@ -0,0 +1,57 @@
+package audio
+
+import (
+	"encoding/binary"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// tone returns d of audio in testFormat at a constant amplitude.
+func tone(d time.Duration, amplitude int16) *WAV {
+	wav := Silence(testFormat, d)
+	for i := 0; i < len(wav.Data); i += 2 {
+		binary.LittleEndian.PutUint16(wav.Data[i:], uint16(amplitude))
+	}
+	return wav
+}
+
+func TestSplitPrefersSilence(t *testing.T) {
+	wav, err := Concat(tone(3500*time.Millisecond, 8000), tone(200*time.Millisecond, 0), tone(6300*time.Millisecond, 8000))
+	require.NoError(t, err)
+
+	windows, err := Split(wav, SplitOptions{MaxDuration: 4 * time.Second, Overlap: 500 * time.Millisecond, SearchRange: time.Second})
+	require.NoError(t, err)
+	require.Len(t, windows, 3)
+
+	assert.Equal(t, time.Duration(0), windows[0].Start)
+	// The first window ends in the pause between 3.5s and 3.7s.
+	assert.Greater(t, windows[0].End, 3500*time.Millisecond)
+	assert.Less(t, windows[0].End, 3700*time.Millisecond)
+	assert.Equal(t, windows[0].End-500*time.Millisecond, windows[1].Start)
+	assert.Equal(t, 10*time.Second, windows[2].End)
+	for _, window := range windows {
+		assert.LessOrEqual(t, window.WAV.Duration(), 4*time.Second)
+		assert.Equal(t, window.End-window.Start, window.WAV.Duration())
+	}
+}
+
+func TestSplitShortAudio(t *testing.T) {
+	wav := tone(time.Second, 100)
+	windows, err := Split(wav, SplitOptions{MaxDuration: 4 * time.Second})
+	require.NoError(t, err)
+	require.Len(t, windows, 1)
+	assert.Equal(t, wav.Data, windows[0].WAV.Data)
+
+	_, err = Split(wav, SplitOptions{MaxDuration: time.Second, Overlap: time.Second})
+	assert.Error(t, err)
+}
+
+func TestSlice(t *testing.T) {
+	wav := &WAV{Format: testFormat, Data: make([]byte, 32000)}
+	assert.Equal(t, 16000, wav.Frames())
+	assert.Equal(t, 250*time.Millisecond, wav.Slice(250*time.Millisecond, 500*time.Millisecond).Duration())
+	assert.Equal(t, 500*time.Millisecond, wav.Slice(500*time.Millisecond, 2*time.Second).Duration())
+}
@@ -0,0 +1,141 @@
+package audio
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// ReadHeader reads the header of a WAV file from r, up to and including the header of the
+// data chunk, leaving r positioned at the first sample. It returns the format, the data
+// size declared in the header and the number of bytes read.
+func ReadHeader(r io.Reader) (format Format, dataSize int64, headerSize int64, err error) {
+	var riff [12]byte
+	if _, err := io.ReadFull(r, riff[:]); err != nil {
+		if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+			return Format{}, 0, 0, ErrNotWAV
+		}
+		return Format{}, 0, 0, err
+	}
+	if string(riff[0:4]) != "RIFF" || string(riff[8:12]) != "WAVE" {
+		return Format{}, 0, 0, ErrNotWAV
+	}
+	headerSize = 12
+
+	seenFmt := false
+	for {
+		var chunk [8]byte
+		if _, err := io.ReadFull(r, chunk[:]); err != nil {
+			return Format{}, 0, 0, fmt.Errorf("audio: reading chunk header: %w", err)
+		}
+		headerSize += 8
+		id := string(chunk[0:4])
+		size := int64(binary.LittleEndian.Uint32(chunk[4:8]))
+
+		switch id {
+		case "data":
+			if !seenFmt {
+				return Format{}, 0, 0, errors.New("audio: data chunk before fmt chunk")
+			}
+			return format, size, headerSize, nil
+		case "fmt ":
+			body := make([]byte, size+size%2)
+			if _, err := io.ReadFull(r, body); err != nil {
+				return Format{}, 0, 0, fmt.Errorf("audio: reading fmt chunk: %w", err)
+			}
+			if size < 16 {
+				return Format{}, 0, 0, errors.New("audio: fmt chunk is too short")
+			}
+			format = Format{
+				AudioFormat:   binary.LittleEndian.Uint16(body[0:2]),
+				Channels:      binary.LittleEndian.Uint16(body[2:4]),
+				SampleRate:    binary.LittleEndian.Uint32(body[4:8]),
+				BitsPerSample: binary.LittleEndian.Uint16(body[14:16]),
+			}
+			if format.AudioFormat == formatExtensible && size >= 26 {
+				// The actual format is the first two bytes of the sub-format GUID.
+				format.AudioFormat = binary.LittleEndian.Uint16(body[24:26])
+			}
+			seenFmt = true
+			headerSize += int64(len(body))
+		default:
+			skipped, err := io.CopyN(io.Discard, r, size+size%2)
+			headerSize += skipped
+			if err != nil {
+				return Format{}, 0, 0, fmt.Errorf("audio: skipping %q chunk: %w", id, err)
+			}
+		}
+	}
+}
+
+// Source is a WAV file to be streamed by NewMergeReader.
+type Source struct {
+	// Reader returns the file's contents. It is called once.
+	Reader func() io.Reader
+	// Size is the size of the file in bytes, used to work out the size of the data chunk
+	// when the header does not declare it correctly.
+	Size int64
+}
+
+// NewMergeReader returns a reader that produces the WAV files of sources merged into one
+// with correct headers, reading each file only as the merged output is consumed.
+// All sources must share the same format; otherwise reading fails with a *FormatMismatchError.
+//
+// The headers of all sources are read when the first byte is requested, so that the total
+// size can be written in the merged header.
+func NewMergeReader(sources ...Source) io.Reader {
+	return &mergeReader{sources: sources}
+}
+
+type mergeReader struct {
+	sources []Source
+	reader  io.Reader
+	err     error
+}
+
+func (m *mergeReader) Read(p []byte) (int, error) {
+	if m.reader == nil && m.err == nil {
+		m.reader, m.err = m.open()
+	}
+	if m.err != nil {
+		return 0, m.err
+	}
+	return m.reader.Read(p)
+}
+
+// open reads the header of every source and chains the merged header with their data.
+func (m *mergeReader) open() (io.Reader, error) {
+	if len(m.sources) == 0 {
+		return nil, errors.New("audio: nothing to merge")
+	}
+
+	var (
+		format  Format
+		total   int64
+		readers = make([]io.Reader, 0, len(m.sources)+1)
+	)
+	for i, source := range m.sources {
+		r := source.Reader()
+		f, declared, headerSize, err := ReadHeader(r)
+		if err != nil {
+			return nil, fmt.Errorf("input %d: %w", i, err)
+		}
+		if i == 0 {
+			format = f
+		} else if f != format {
+			return nil, &FormatMismatchError{Index: i, Want: format, Got: f}
+		}
+
+		size := declared
+		if source.Size > 0 {
+			size = min(size, source.Size-headerSize)
+		}
+		readers = append(readers, io.LimitReader(r, size))
+		total += size
+	}
+
+	readers = append([]io.Reader{bytes.NewReader(Header(format, int(total)))}, readers...)
+	return io.MultiReader(readers...), nil
+}
@@ -0,0 +1,227 @@
+// Package audio provides helpers for the WAV audio produced and consumed by the Sarvam AI API.
+package audio
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+)
+
+// ErrNotWAV is returned when data does not hold a RIFF/WAVE file.
+var ErrNotWAV = errors.New("audio: not a WAV file")
+
+// Audio formats stored in the fmt chunk of a WAV file.
+const (
+	FormatPCM        uint16 = 1
+	FormatIEEEFloat  uint16 = 3
+	formatExtensible uint16 = 0xFFFE
+)
+
+// Format describes how samples are encoded in a WAV file.
+type Format struct {
+	AudioFormat   uint16 // FormatPCM or FormatIEEEFloat
+	Channels      uint16
+	SampleRate    uint32
+	BitsPerSample uint16
+}
+
+// BlockAlign returns the number of bytes in one frame, holding a sample for each channel.
+func (f Format) BlockAlign() int {
+	return int(f.Channels) * int(f.BitsPerSample) / 8
+}
+
+// ByteRate returns the number of bytes of audio per second.
+func (f Format) ByteRate() int {
+	return int(f.SampleRate) * f.BlockAlign()
+}
+
+func (f Format) String() string {
+	return fmt.Sprintf("%d Hz, %d-bit, %d channel(s)", f.SampleRate, f.BitsPerSample, f.Channels)
+}
+
+// WAV holds the format and sample data of a WAV file.
+type WAV struct {
+	Format Format
+	Data   []byte // Raw sample data, little-endian and interleaved by channel
+}
+
+// Duration returns the length of the audio.
+func (w *WAV) Duration() time.Duration {
+	byteRate := w.Format.ByteRate()
+	if byteRate == 0 {
+		return 0
+	}
+	return time.Duration(len(w.Data)) * time.Second / time.Duration(byteRate)
+}
+
+// ParseWAV parses a WAV file. Chunks other than fmt and data are skipped. A data chunk
+// whose declared size exceeds the file, as written by streaming encoders, is truncated to
+// the bytes present.
+func ParseWAV(data []byte) (*WAV, error) {
+	if len(data) < 12 || string(data[0:4]) != "RIFF" || string(data[8:12]) != "WAVE" {
+		return nil, ErrNotWAV
+	}
+
+	var (
+		wav       WAV
+		seenFmt   bool
+		seenData  bool
+		remaining = data[12:]
+	)
+	for len(remaining) >= 8 && !seenData {
+		id := string(remaining[0:4])
+		size := int64(binary.LittleEndian.Uint32(remaining[4:8]))
+		remaining = remaining[8:]
+		if size > int64(len(remaining)) {
+			if id != "data" {
+				return nil, fmt.Errorf("audio: %q chunk is truncated", id)
+			}
+			size = int64(len(remaining))
+		}
+		body := remaining[:size]
+
+		switch id {
+		case "fmt ":
+			if len(body) < 16 {
+				return nil, errors.New("audio: fmt chunk is too short")
+			}
+			wav.Format = Format{
+				AudioFormat:   binary.LittleEndian.Uint16(body[0:2]),
+				Channels:      binary.LittleEndian.Uint16(body[2:4]),
+				SampleRate:    binary.LittleEndian.Uint32(body[4:8]),
+				BitsPerSample: binary.LittleEndian.Uint16(body[14:16]),
+			}
+			if wav.Format.AudioFormat == formatExtensible && len(body) >= 26 {
+				// The actual format is the first two bytes of the sub-format GUID.
+				wav.Format.AudioFormat = binary.LittleEndian.Uint16(body[24:26])
+			}
+			seenFmt = true
+		case "data":
+			if !seenFmt {
+				return nil, errors.New("audio: data chunk before fmt chunk")
+			}
+			wav.Data = body
+			seenData = true
+		}
+
+		// Chunks are padded to an even size.
+		if size%2 == 1 && size < int64(len(remaining)) {
+			size++
+		}
+		remaining = remaining[size:]
+	}
+
+	if !seenFmt {
+		return nil, errors.New("audio: missing fmt chunk")
+	}
+	if !seenData {
+		return nil, errors.New("audio: missing data chunk")
+	}
+	return &wav, nil
+}
+
+// headerSize is the size of the header written by WriteTo.
+const headerSize = 44
+
+// WriteTo writes the audio as a canonical WAV file with a single fmt and data chunk.
+func (w *WAV) WriteTo(dst io.Writer) (int64, error) {
+	n, err := dst.Write(Header(w.Format, len(w.Data)))
+	if err != nil {
+		return int64(n), err
+	}
+	m, err := dst.Write(w.Data)
+	return int64(n + m), err
+}
+
+// Bytes returns the audio encoded as a canonical WAV file.
+func (w *WAV) Bytes() []byte {
+	var buf bytes.Buffer
+	buf.Grow(headerSize + len(w.Data))
+	_, _ = w.WriteTo(&buf)
+	return buf.Bytes()
+}
+
+// Header returns the 44 byte header of a WAV file holding dataSize bytes of audio in format.
+func Header(format Format, dataSize int) []byte {
+	header := make([]byte, headerSize)
+	copy(header[0:4], "RIFF")
+	binary.LittleEndian.PutUint32(header[4:8], uint32(headerSize-8+dataSize))
+	copy(header[8:12], "WAVE")
+	copy(header[12:16], "fmt ")
+	binary.LittleEndian.PutUint32(header[16:20], 16)
+	binary.LittleEndian.PutUint16(header[20:22], format.AudioFormat)
+	binary.LittleEndian.PutUint16(header[22:24], format.Channels)
+	binary.LittleEndian.PutUint32(header[24:28], format.SampleRate)
+	binary.LittleEndian.PutUint32(header[28:32], uint32(format.ByteRate()))
+	binary.LittleEndian.PutUint16(header[32:34], uint16(format.BlockAlign()))
+	binary.LittleEndian.PutUint16(header[34:36], format.BitsPerSample)
+	copy(header[36:40], "data")
+	binary.LittleEndian.PutUint32(header[40:44], uint32(dataSize))
+	return header
+}
+
+// FormatMismatchError is returned when audio that is being merged uses different formats.
+type FormatMismatchError struct {
+	Index int    // Index of the first input whose format differs
+	Want  Format // Format of the first input
+	Got   Format // Format of the input at Index
+}
+
+func (e *FormatMismatchError) Error() string {
+	return fmt.Sprintf("audio: input %d is %s, want %s", e.Index, e.Got, e.Want)
+}
+
+// Concat joins the sample data of wavs, which must all have the same format.
+func Concat(wavs ...*WAV) (*WAV, error) {
+	if len(wavs) == 0 {
+		return nil, errors.New("audio: nothing to concatenate")
+	}
+
+	size := 0
+	for i, wav := range wavs {
+		if wav.Format != wavs[0].Format {
+			return nil, &FormatMismatchError{Index: i, Want: wavs[0].Format, Got: wav.Format}
+		}
+		size += len(wav.Data)
+	}
+
+	data := make([]byte, 0, size)
+	for _, wav := range wavs {
+		data = append(data, wav.Data...)
+	}
+	return &WAV{Format: wavs[0].Format, Data: data}, nil
+}
+
+// MergeWAV parses WAV files and joins them into a single WAV file with correct headers.
+func MergeWAV(files ...[]byte) ([]byte, error) {
+	wavs := make([]*WAV, len(files))
+	for i, file := range files {
+		wav, err := ParseWAV(file)
+		if err != nil {
+			return nil, fmt.Errorf("input %d: %w", i, err)
+		}
+		wavs[i] = wav
+	}
+
+	merged, err := Concat(wavs...)
+	if err != nil {
+		return nil, err
+	}
+	return merged.Bytes(), nil
+}
+
+// Silence returns d of silent audio in format, rounded to the nearest frame.
+func Silence(format Format, d time.Duration) *WAV {
+	frames := int((int64(d)*int64(format.SampleRate) + int64(time.Second)/2) / int64(time.Second))
+	data := make([]byte, frames*format.BlockAlign())
+	if format.AudioFormat == FormatPCM && format.BitsPerSample == 8 {
+		// 8-bit PCM is unsigned, with silence in the middle of the range.
+		for i := range data {
+			data[i] = 0x80
+		}
+	}
+	return &WAV{Format: format, Data: data}
+}
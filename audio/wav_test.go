@@ -0,0 +1,130 @@
+package audio
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testFormat = Format{AudioFormat: FormatPCM, Channels: 1, SampleRate: 16000, BitsPerSample: 16}
+
+func TestWAVRoundTrip(t *testing.T) {
+	wav := &WAV{Format: testFormat, Data: []byte{1, 2, 3, 4}}
+	data := wav.Bytes()
+	assert.Len(t, data, 48)
+	assert.Equal(t, uint32(40), binary.LittleEndian.Uint32(data[4:8]))
+
+	parsed, err := ParseWAV(data)
+	require.NoError(t, err)
+	assert.Equal(t, wav, parsed)
+}
+
+func TestParseWAVSkipsChunks(t *testing.T) {
+	var buf bytes.Buffer
+	buf.WriteString("RIFF\x00\x00\x00\x00WAVE")
+	buf.WriteString("LIST\x03\x00\x00\x00abc\x00") // odd-sized chunk with padding
+	buf.Write(Header(testFormat, 0)[12:36])
+	buf.WriteString("data\xff\xff\xff\xff") // unknown length, as written by streaming encoders
+	buf.Write([]byte{9, 8, 7, 6})
+
+	wav, err := ParseWAV(buf.Bytes())
+	require.NoError(t, err)
+	assert.Equal(t, testFormat, wav.Format)
+	assert.Equal(t, []byte{9, 8, 7, 6}, wav.Data)
+}
+
+func TestParseWAVErrors(t *testing.T) {
+	_, err := ParseWAV([]byte("ID3\x04 not a wav file"))
+	assert.ErrorIs(t, err, ErrNotWAV)
+
+	_, err = ParseWAV([]byte("RIFF\x04\x00\x00\x00WAVE"))
+	assert.ErrorContains(t, err, "missing fmt chunk")
+
+	_, err = ParseWAV(Header(testFormat, 0)[:36])
+	assert.ErrorContains(t, err, "missing data chunk")
+}
+
+func TestMergeWAV(t *testing.T) {
+	first := (&WAV{Format: testFormat, Data: []byte{1, 2}}).Bytes()
+	second := (&WAV{Format: testFormat, Data: []byte{3, 4, 5, 6}}).Bytes()
+
+	merged, err := MergeWAV(first, second)
+	require.NoError(t, err)
+	assert.Equal(t, (&WAV{Format: testFormat, Data: []byte{1, 2, 3, 4, 5, 6}}).Bytes(), merged)
+	assert.Equal(t, []byte("RIFF"), merged[:4])
+	assert.Equal(t, 1, bytes.Count(merged, []byte("RIFF")))
+}
+
+func TestMergeWAVFormatMismatch(t *testing.T) {
+	other := testFormat
+	other.SampleRate = 22050
+
+	_, err := MergeWAV((&WAV{Format: testFormat}).Bytes(), (&WAV{Format: other}).Bytes())
+	var mismatch *FormatMismatchError
+	require.True(t, errors.As(err, &mismatch))
+	assert.Equal(t, 1, mismatch.Index)
+	assert.Equal(t, "audio: input 1 is 22050 Hz, 16-bit, 1 channel(s), want 16000 Hz, 16-bit, 1 channel(s)", err.Error())
+
+	_, err = MergeWAV((&WAV{Format: testFormat}).Bytes(), []byte("garbage"))
+	assert.ErrorIs(t, err, ErrNotWAV)
+}
+
+func TestDuration(t *testing.T) {
+	wav := &WAV{Format: testFormat, Data: make([]byte, 16000)}
+	assert.Equal(t, 500*time.Millisecond, wav.Duration())
+	assert.Zero(t, (&WAV{}).Duration())
+}
+
+func TestSilence(t *testing.T) {
+	silence := Silence(testFormat, 250*time.Millisecond)
+	assert.Len(t, silence.Data, 8000)
+	assert.Equal(t, 250*time.Millisecond, silence.Duration())
+	assert.Equal(t, make([]byte, 8000), silence.Data)
+
+	eightBit := Format{AudioFormat: FormatPCM, Channels: 1, SampleRate: 8000, BitsPerSample: 8}
+	assert.Equal(t, bytes.Repeat([]byte{0x80}, 80), Silence(eightBit, 10*time.Millisecond).Data)
+}
+
+func TestReadHeader(t *testing.T) {
+	var buf bytes.Buffer
+	buf.WriteString("RIFF\x00\x00\x00\x00WAVE")
+	buf.WriteString("LIST\x03\x00\x00\x00abc\x00")
+	buf.Write(Header(testFormat, 4)[12:])
+	buf.Write([]byte{9, 8, 7, 6})
+
+	r := bytes.NewReader(buf.Bytes())
+	format, dataSize, headerSize, err := ReadHeader(r)
+	require.NoError(t, err)
+	assert.Equal(t, testFormat, format)
+	assert.Equal(t, int64(4), dataSize)
+	assert.Equal(t, int64(buf.Len()-4), headerSize)
+	assert.Equal(t, 4, r.Len())
+
+	_, _, _, err = ReadHeader(bytes.NewReader([]byte("ID3")))
+	assert.ErrorIs(t, err, ErrNotWAV)
+}
+
+func TestNewMergeReader(t *testing.T) {
+	first := (&WAV{Format: testFormat, Data: []byte{1, 2}}).Bytes()
+	second := (&WAV{Format: testFormat, Data: []byte{3, 4, 5, 6}}).Bytes()
+	binary.LittleEndian.PutUint32(second[40:44], 0xFFFFFFFF) // unknown length
+
+	source := func(data []byte) Source {
+		return Source{Reader: func() io.Reader { return bytes.NewReader(data) }, Size: int64(len(data))}
+	}
+	merged, err := io.ReadAll(NewMergeReader(source(first), source(second)))
+	require.NoError(t, err)
+	assert.Equal(t, (&WAV{Format: testFormat, Data: []byte{1, 2, 3, 4, 5, 6}}).Bytes(), merged)
+
+	other := testFormat
+	other.Channels = 2
+	_, err = io.ReadAll(NewMergeReader(source(first), source((&WAV{Format: other}).Bytes())))
+	var mismatch *FormatMismatchError
+	assert.ErrorAs(t, err, &mismatch)
+}
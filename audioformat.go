@@ -0,0 +1,138 @@
+package sarvam
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/textproto"
+	"slices"
+)
+
+// audioFormat describes how an audio container is uploaded to the speech-to-text endpoints.
+type audioFormat struct {
+	codec       AudioCodec
+	extension   string
+	contentType string
+}
+
+var (
+	audioFormatWAV  = audioFormat{AudioCodecWav, ".wav", "audio/wav"}
+	audioFormatMP3  = audioFormat{AudioCodecMp3, ".mp3", "audio/mpeg"}
+	audioFormatAAC  = audioFormat{AudioCodecAac, ".aac", "audio/aac"}
+	audioFormatOgg  = audioFormat{AudioCodecOgg, ".ogg", "audio/ogg"}
+	audioFormatOpus = audioFormat{AudioCodecOpus, ".opus", "audio/ogg"}
+	audioFormatFLAC = audioFormat{AudioCodecFlac, ".flac", "audio/flac"}
+	audioFormatMP4  = audioFormat{AudioCodecMp4, ".mp4", "audio/mp4"}
+	audioFormatM4A  = audioFormat{AudioCodecXM4a, ".m4a", "audio/mp4"}
+	audioFormatWebM = audioFormat{AudioCodecWebm, ".webm", "audio/webm"}
+	audioFormatAIFF = audioFormat{AudioCodecAiff, ".aiff", "audio/aiff"}
+	audioFormatAMR  = audioFormat{AudioCodecAmr, ".amr", "audio/amr"}
+	audioFormatWMA  = audioFormat{AudioCodecXMsWma, ".wma", "audio/x-ms-wma"}
+	audioFormatPCM  = audioFormat{AudioCodecPcmS16le, ".pcm", "application/octet-stream"}
+)
+
+// audioSniffLength is the number of leading bytes inspected to detect the audio format.
+const audioSniffLength = 64
+
+// asfHeaderGUID starts Windows Media (ASF) files.
+var asfHeaderGUID = []byte{0x30, 0x26, 0xB2, 0x75, 0x8E, 0x66, 0xCF, 0x11}
+
+// UnsupportedAudioFormatError is returned when speech-to-text input is not in a container
+// the API accepts. Raw PCM must be labelled with a PCM AudioCodec, since it has no header
+// to recognise it by.
+type UnsupportedAudioFormatError struct {
+	Header []byte // Leading bytes of the input
+}
+
+func (e *UnsupportedAudioFormatError) Error() string {
+	if len(e.Header) == 0 {
+		return "unsupported audio format: input is empty"
+	}
+	return fmt.Sprintf("unsupported audio format: input starts with % x", e.Header[:min(len(e.Header), 12)])
+}
+
+// sniffAudioFormat detects the container of audio from its leading bytes.
+func sniffAudioFormat(header []byte) (audioFormat, bool) {
+	switch {
+	case len(header) >= 12 && string(header[0:4]) == "RIFF" && string(header[8:12]) == "WAVE":
+		return audioFormatWAV, true
+	case len(header) >= 12 && string(header[0:4]) == "FORM" && (string(header[8:12]) == "AIFF" || string(header[8:12]) == "AIFC"):
+		return audioFormatAIFF, true
+	case bytes.HasPrefix(header, []byte("ID3")):
+		return audioFormatMP3, true
+	case bytes.HasPrefix(header, []byte("OggS")):
+		if len(header) >= 36 && string(header[28:36]) == "OpusHead" {
+			return audioFormatOpus, true
+		}
+		return audioFormatOgg, true
+	case bytes.HasPrefix(header, []byte("fLaC")):
+		return audioFormatFLAC, true
+	case len(header) >= 12 && string(header[4:8]) == "ftyp":
+		if brand := string(header[8:12]); brand == "M4A " || brand == "M4B " {
+			return audioFormatM4A, true
+		}
+		return audioFormatMP4, true
+	case bytes.HasPrefix(header, []byte{0x1A, 0x45, 0xDF, 0xA3}):
+		return audioFormatWebM, true
+	case bytes.HasPrefix(header, []byte("#!AMR")):
+		return audioFormatAMR, true
+	case bytes.HasPrefix(header, asfHeaderGUID):
+		return audioFormatWMA, true
+	case len(header) >= 2 && header[0] == 0xFF && header[1]&0xE0 == 0xE0:
+		// MPEG audio frame sync. Layer bits of zero mark an ADTS (AAC) frame instead.
+		if header[1]&0x06 == 0 {
+			return audioFormatAAC, true
+		}
+		return audioFormatMP3, true
+	}
+	return audioFormat{}, false
+}
+
+// isPCMCodec reports whether codec is one of the headerless PCM codecs.
+func isPCMCodec(codec AudioCodec) bool {
+	return slices.Contains([]AudioCodec{AudioCodecPcmS16le, AudioCodecPcmL16, AudioCodecPcmRaw}, codec)
+}
+
+// detectAudioFormat determines how to upload speech. An explicit PCM codec skips detection;
+// any other explicit codec overrides the detected one. It returns a reader yielding the
+// whole input, including the bytes read for detection.
+func detectAudioFormat(speech io.Reader, codec *AudioCodec) (audioFormat, io.Reader, error) {
+	if codec != nil && isPCMCodec(*codec) {
+		format := audioFormatPCM
+		format.codec = *codec
+		return format, speech, nil
+	}
+
+	header := make([]byte, audioSniffLength)
+	n, err := io.ReadFull(speech, header)
+	if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+		return audioFormat{}, nil, fmt.Errorf("failed to read audio: %w", err)
+	}
+	header = header[:n]
+	speech = io.MultiReader(bytes.NewReader(header), speech)
+
+	format, ok := sniffAudioFormat(header)
+	if !ok {
+		return audioFormat{}, nil, &UnsupportedAudioFormatError{Header: header}
+	}
+	if codec != nil {
+		format.codec = *codec
+	}
+	return format, speech, nil
+}
+
+// writeAudioPart writes speech as the file part of a speech-to-text form.
+func writeAudioPart(writer *multipart.Writer, speech io.Reader, format audioFormat) error {
+	part, err := writer.CreatePart(textproto.MIMEHeader{
+		"Content-Disposition": {fmt.Sprintf(`form-data; name="file"; filename="speech%s"`, format.extension)},
+		"Content-Type":        {format.contentType},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create form file: %w", err)
+	}
+	if _, err := io.Copy(part, speech); err != nil {
+		return fmt.Errorf("failed to copy file content: %w", err)
+	}
+	return nil
+}
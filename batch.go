@@ -0,0 +1,418 @@
+package sarvam
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"path"
+	"time"
+)
+
+// ErrJobFailed is returned when waiting for a batch job that ends in the Failed state.
+var ErrJobFailed = errors.New("batch job failed")
+
+// JobState represents the state of a batch speech-to-text job.
+type JobState string
+
+const (
+	JobStateAccepted  JobState = "Accepted"
+	JobStatePending   JobState = "Pending"
+	JobStateRunning   JobState = "Running"
+	JobStateCompleted JobState = "Completed"
+	JobStateFailed    JobState = "Failed"
+)
+
+// Done reports whether the job has finished, successfully or not.
+func (s JobState) Done() bool {
+	return s == JobStateCompleted || s == JobStateFailed
+}
+
+// JobFileRef identifies a file belonging to a batch job.
+type JobFileRef struct {
+	FileName string `json:"file_name"`
+	FileID   string `json:"file_id"`
+}
+
+// JobTaskStatus describes the processing of one input file of a batch job.
+type JobTaskStatus struct {
+	Inputs       []JobFileRef `json:"inputs"`
+	Outputs      []JobFileRef `json:"outputs"`
+	State        string       `json:"state"`
+	ErrorMessage string       `json:"error_message,omitempty"`
+}
+
+// JobStatus represents the status of a batch speech-to-text job.
+type JobStatus struct {
+	JobID           string          `json:"job_id"`
+	State           JobState        `json:"job_state"`
+	TotalFiles      int             `json:"total_files"`
+	SuccessfulFiles int             `json:"successful_files_count"`
+	FailedFiles     int             `json:"failed_files_count"`
+	ErrorMessage    string          `json:"error_message,omitempty"`
+	Tasks           []JobTaskStatus `json:"job_details"`
+}
+
+// JobFile is an audio file to upload to a batch job.
+type JobFile struct {
+	Name   string // File name, unique within the job
+	Reader io.Reader
+}
+
+// SpeechToTextJob is a handle to a batch speech-to-text job, which transcribes many
+// audio files asynchronously.
+//
+// A job is used by uploading its files, starting it, waiting for it to complete and
+// downloading the results. Its ID can be stored to resume waiting for it later with
+// Client.SpeechToTextJob.
+type SpeechToTextJob struct {
+	ID     string
+	client *Client
+}
+
+// CreateSpeechToTextJob creates a batch speech-to-text job that transcribes its files with params.
+func (c *Client) CreateSpeechToTextJob(params SpeechToTextParams) (*SpeechToTextJob, error) {
+	return c.CreateSpeechToTextJobWithContext(context.Background(), params)
+}
+
+// CreateSpeechToTextJobWithContext is like CreateSpeechToTextJob but uses ctx to control cancellation and deadlines.
+func (c *Client) CreateSpeechToTextJobWithContext(ctx context.Context, params SpeechToTextParams) (*SpeechToTextJob, error) {
+	if params.NumSpeakers != nil && *params.NumSpeakers < 1 {
+		return nil, fmt.Errorf("num_speakers must be at least 1, got %d", *params.NumSpeakers)
+	}
+	// Batch jobs run the models of the speech-to-text endpoint.
+	if err := validateSpeechToTextModel(EndpointSpeechToText, modelID(params.Model), params.Language); err != nil {
+		return nil, err
+	}
+
+	type jobParameters struct {
+		Model           *SpeechToTextModel `json:"model,omitempty"`
+		Language        *Language          `json:"language_code,omitempty"`
+		WithTimestamps  *bool              `json:"with_timestamps,omitempty"`
+		WithDiarization *bool              `json:"with_diarization,omitempty"`
+		NumSpeakers     *int               `json:"num_speakers,omitempty"`
+	}
+	payload := map[string]any{
+		"job_parameters": jobParameters{
+			Model:           params.Model,
+			Language:        params.Language,
+			WithTimestamps:  params.WithTimestamps,
+			WithDiarization: params.WithDiarization,
+			NumSpeakers:     params.NumSpeakers,
+		},
+	}
+
+	var response struct {
+		JobID string `json:"job_id"`
+	}
+	if err := c.doJobRequest(ctx, http.MethodPost, EndpointSpeechToTextJob, payload, &response); err != nil {
+		return nil, err
+	}
+	if response.JobID == "" {
+		return nil, fmt.Errorf("response has no job ID")
+	}
+	return c.SpeechToTextJob(response.JobID), nil
+}
+
+// SpeechToTextJob returns a handle to the existing batch job with the given ID.
+func (c *Client) SpeechToTextJob(id string) *SpeechToTextJob {
+	return &SpeechToTextJob{ID: id, client: c}
+}
+
+// Upload uploads audio files to the job. It must be called before Start.
+func (j *SpeechToTextJob) Upload(files ...JobFile) error {
+	return j.UploadWithContext(context.Background(), files...)
+}
+
+// UploadWithContext is like Upload but uses ctx to control cancellation and deadlines.
+func (j *SpeechToTextJob) UploadWithContext(ctx context.Context, files ...JobFile) error {
+	names := make([]string, len(files))
+	for i, file := range files {
+		names[i] = file.Name
+	}
+	urls, err := j.fileURLs(ctx, EndpointSpeechToTextJob+"/upload-files", names)
+	if err != nil {
+		return err
+	}
+
+	for _, file := range files {
+		data, err := io.ReadAll(file.Reader)
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %w", file.Name, err)
+		}
+		if err := j.client.uploadFile(ctx, urls[file.Name], data); err != nil {
+			return fmt.Errorf("failed to upload %s: %w", file.Name, err)
+		}
+	}
+	return nil
+}
+
+// Start starts processing the uploaded files.
+func (j *SpeechToTextJob) Start() (*JobStatus, error) {
+	return j.StartWithContext(context.Background())
+}
+
+// StartWithContext is like Start but uses ctx to control cancellation and deadlines.
+func (j *SpeechToTextJob) StartWithContext(ctx context.Context) (*JobStatus, error) {
+	var status JobStatus
+	if err := j.client.doJobRequest(ctx, http.MethodPost, j.endpoint("start"), nil, &status); err != nil {
+		return nil, err
+	}
+	return &status, nil
+}
+
+// Status returns the current status of the job.
+func (j *SpeechToTextJob) Status() (*JobStatus, error) {
+	return j.StatusWithContext(context.Background())
+}
+
+// StatusWithContext is like Status but uses ctx to control cancellation and deadlines.
+func (j *SpeechToTextJob) StatusWithContext(ctx context.Context) (*JobStatus, error) {
+	var status JobStatus
+	if err := j.client.doJobRequest(ctx, http.MethodGet, j.endpoint("status"), nil, &status); err != nil {
+		return nil, err
+	}
+	return &status, nil
+}
+
+// JobWaitOptions contains optional settings for SpeechToTextJob.Wait.
+type JobWaitOptions struct {
+	PollInterval    time.Duration          // Initial delay between polls; defaults to 2 seconds
+	MaxPollInterval time.Duration          // Upper bound of the growing delay between polls; defaults to 30 seconds
+	OnStatus        func(status JobStatus) // Called with every status received
+}
+
+const (
+	defaultJobPollInterval    = 2 * time.Second
+	defaultJobMaxPollInterval = 30 * time.Second
+	jobPollMultiplier         = 1.5
+)
+
+// Wait polls the status of the job, backing off between polls, until it completes or fails.
+// If the job fails, the final status is returned along with an error wrapping ErrJobFailed.
+// Polls failing with an error that IsRetryable accepts, or with a network error such as a
+// dropped connection, are repeated; other errors end the wait.
+func (j *SpeechToTextJob) Wait(opts *JobWaitOptions) (*JobStatus, error) {
+	return j.WaitWithContext(context.Background(), opts)
+}
+
+// WaitWithContext is like Wait but uses ctx to control cancellation and deadlines.
+func (j *SpeechToTextJob) WaitWithContext(ctx context.Context, opts *JobWaitOptions) (*JobStatus, error) {
+	if opts == nil {
+		opts = &JobWaitOptions{}
+	}
+	interval := opts.PollInterval
+	if interval <= 0 {
+		interval = defaultJobPollInterval
+	}
+	maxInterval := opts.MaxPollInterval
+	if maxInterval <= 0 {
+		maxInterval = defaultJobMaxPollInterval
+	}
+
+	for {
+		delay := interval
+		status, err := j.StatusWithContext(ctx)
+		if err != nil {
+			if !IsRetryable(err) && !isNetworkError(ctx, err) {
+				return nil, err
+			}
+			var httpErr *HTTPError
+			if errors.As(err, &httpErr) {
+				delay = max(delay, httpErr.RetryAfter)
+			}
+		} else {
+			if opts.OnStatus != nil {
+				opts.OnStatus(*status)
+			}
+			switch status.State {
+			case JobStateCompleted:
+				return status, nil
+			case JobStateFailed:
+				return status, fmt.Errorf("%w: %s", ErrJobFailed, status.ErrorMessage)
+			}
+		}
+
+		if err := sleep(ctx, delay); err != nil {
+			return nil, contextError(ctx, err)
+		}
+		interval = min(time.Duration(float64(interval)*jobPollMultiplier), maxInterval)
+	}
+}
+
+// isNetworkError reports whether err is a failure to reach the API, such as a reset
+// connection, a DNS failure or a client timeout, rather than a cancellation of ctx or a
+// request that could not be built.
+func isNetworkError(ctx context.Context, err error) bool {
+	if ctx.Err() != nil || errors.Is(err, ErrRequestCanceled) || errors.Is(err, errBuildRequest) {
+		return false
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr)
+}
+
+// Results downloads the transcripts of the files the job processed successfully, keyed by
+// the name of the input file.
+func (j *SpeechToTextJob) Results() (map[string]*SpeechToTextResponse, error) {
+	return j.ResultsWithContext(context.Background())
+}
+
+// ResultsWithContext is like Results but uses ctx to control cancellation and deadlines.
+func (j *SpeechToTextJob) ResultsWithContext(ctx context.Context) (map[string]*SpeechToTextResponse, error) {
+	status, err := j.StatusWithContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if status.State != JobStateCompleted {
+		return nil, fmt.Errorf("job %s is %s, not %s", j.ID, status.State, JobStateCompleted)
+	}
+
+	var (
+		outputs []string
+		inputOf = make(map[string]string)
+	)
+	for _, task := range status.Tasks {
+		if len(task.Inputs) == 0 {
+			continue
+		}
+		for _, output := range task.Outputs {
+			outputs = append(outputs, output.FileName)
+			inputOf[output.FileName] = task.Inputs[0].FileName
+		}
+	}
+	if len(outputs) == 0 {
+		return map[string]*SpeechToTextResponse{}, nil
+	}
+
+	urls, err := j.fileURLs(ctx, EndpointSpeechToTextJob+"/download-files", outputs)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make(map[string]*SpeechToTextResponse, len(outputs))
+	for _, output := range outputs {
+		data, err := j.client.downloadFile(ctx, urls[output])
+		if err != nil {
+			return nil, fmt.Errorf("failed to download %s: %w", output, err)
+		}
+		if results[inputOf[output]], err = decodeSpeechToTextResponse(bytes.NewReader(data)); err != nil {
+			return nil, fmt.Errorf("failed to decode %s: %w", output, err)
+		}
+	}
+	return results, nil
+}
+
+// endpoint returns the path of an action on the job.
+func (j *SpeechToTextJob) endpoint(action string) string {
+	return path.Join(EndpointSpeechToTextJob, url.PathEscape(j.ID), action)
+}
+
+// fileURLs requests presigned URLs for the named files of the job from endpoint.
+func (j *SpeechToTextJob) fileURLs(ctx context.Context, endpoint string, names []string) (map[string]string, error) {
+	payload := map[string]any{
+		"job_id": j.ID,
+		"files":  names,
+	}
+	var response struct {
+		UploadURLs map[string]struct {
+			FileURL string `json:"file_url"`
+		} `json:"upload_urls"`
+		DownloadURLs map[string]struct {
+			FileURL string `json:"file_url"`
+		} `json:"download_urls"`
+	}
+	if err := j.client.doJobRequest(ctx, http.MethodPost, endpoint, payload, &response); err != nil {
+		return nil, err
+	}
+
+	urls := make(map[string]string, len(names))
+	for name, u := range response.UploadURLs {
+		urls[name] = u.FileURL
+	}
+	for name, u := range response.DownloadURLs {
+		urls[name] = u.FileURL
+	}
+	for _, name := range names {
+		if urls[name] == "" {
+			return nil, fmt.Errorf("response has no URL for %s", name)
+		}
+	}
+	return urls, nil
+}
+
+// doJobRequest sends a request to a batch job endpoint and decodes the response into result.
+func (c *Client) doJobRequest(ctx context.Context, method, endpoint string, payload any, result any) error {
+	var (
+		resp *http.Response
+		err  error
+	)
+	if payload != nil {
+		resp, err = c.makeJsonHTTPRequest(ctx, method, c.baseURL+endpoint, payload)
+	} else {
+		resp, err = c.makeHTTPRequest(ctx, method, c.baseURL+endpoint, nil, "")
+	}
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return parseAPIError(resp)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(result); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+	return nil
+}
+
+// uploadFile sends data to a presigned storage URL.
+func (c *Client) uploadFile(ctx context.Context, fileURL string, data []byte) error {
+	resp, err := c.storageRequest(ctx, http.MethodPut, fileURL, data)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	return nil
+}
+
+// downloadFile fetches the contents of a presigned storage URL.
+func (c *Client) downloadFile(ctx context.Context, fileURL string) ([]byte, error) {
+	resp, err := c.storageRequest(ctx, http.MethodGet, fileURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	return io.ReadAll(resp.Body)
+}
+
+// storageRequest sends a request to a presigned storage URL. Unlike requests to the API,
+// it carries neither the API key nor custom headers, since the URL holds its own
+// authorization and may point to a third party.
+func (c *Client) storageRequest(ctx context.Context, method, fileURL string, body []byte) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, method, fileURL, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	if c.userAgent != "" {
+		req.Header.Set("User-Agent", c.userAgent)
+	}
+	if method == http.MethodPut {
+		// Presigned URLs point to Azure Blob Storage, which requires the blob type on upload.
+		req.Header.Set("x-ms-blob-type", "BlockBlob")
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, contextError(ctx, err)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		defer resp.Body.Close()
+		return nil, parseAPIError(resp)
+	}
+	return resp, nil
+}
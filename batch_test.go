@@ -0,0 +1,246 @@
+package sarvam
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeJobServer is a stand-in for the batch speech-to-text API and its file storage.
+type fakeJobServer struct {
+	*httptest.Server
+	t *testing.T
+
+	mu        sync.Mutex
+	params    map[string]any
+	uploads   map[string]string
+	started   bool
+	polls     int
+	pollsLeft int // Status polls reporting Running before the job completes
+	fail      bool
+
+	statusErrors []int // Status codes of the errors returned by the next status polls
+	dropPolls    int   // Status polls whose connection is closed without a response
+}
+
+func newFakeJobServer(t *testing.T) *fakeJobServer {
+	t.Helper()
+	f := &fakeJobServer{t: t, uploads: make(map[string]string), pollsLeft: 2}
+	f.Server = httptest.NewServer(http.HandlerFunc(f.handle))
+	t.Cleanup(f.Close)
+	return f
+}
+
+func (f *fakeJobServer) handle(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if name, ok := strings.CutPrefix(r.URL.Path, "/storage/"); ok {
+		assert.Empty(f.t, r.Header.Get("api-subscription-key"), "storage requests must not carry the API key")
+		switch r.Method {
+		case http.MethodPut:
+			assert.Equal(f.t, "BlockBlob", r.Header.Get("x-ms-blob-type"))
+			data, _ := io.ReadAll(r.Body)
+			f.uploads[name] = string(data)
+			w.WriteHeader(http.StatusCreated)
+		case http.MethodGet:
+			input := strings.TrimSuffix(name, ".json")
+			json.NewEncoder(w).Encode(map[string]any{
+				"request_id":    "req-" + input,
+				"transcript":    "transcript of " + f.uploads[input],
+				"language_code": "hi-IN",
+			})
+		}
+		return
+	}
+
+	assert.Equal(f.t, "test", r.Header.Get("api-subscription-key"))
+	var body struct {
+		JobParameters map[string]any `json:"job_parameters"`
+		JobID         string         `json:"job_id"`
+		Files         []string       `json:"files"`
+	}
+	if r.Body != nil {
+		json.NewDecoder(r.Body).Decode(&body)
+	}
+	fileURLs := func() map[string]any {
+		urls := make(map[string]any)
+		for _, name := range body.Files {
+			urls[name] = map[string]string{"file_url": f.URL + "/storage/" + name}
+		}
+		return urls
+	}
+
+	switch r.URL.Path {
+	case "/speech-to-text/job/v1":
+		f.params = body.JobParameters
+		json.NewEncoder(w).Encode(map[string]any{"job_id": "job-1", "job_state": "Accepted"})
+	case "/speech-to-text/job/v1/upload-files":
+		assert.Equal(f.t, "job-1", body.JobID)
+		json.NewEncoder(w).Encode(map[string]any{"job_id": "job-1", "upload_urls": fileURLs()})
+	case "/speech-to-text/job/v1/download-files":
+		json.NewEncoder(w).Encode(map[string]any{"job_id": "job-1", "download_urls": fileURLs()})
+	case "/speech-to-text/job/v1/job-1/start":
+		f.started = true
+		json.NewEncoder(w).Encode(JobStatus{JobID: "job-1", State: JobStatePending})
+	case "/speech-to-text/job/v1/job-1/status":
+		assert.NotContains(f.t, r.Header, "Content-Type", "requests without a body must not set a content type")
+		f.polls++
+		if f.dropPolls > 0 {
+			f.dropPolls--
+			conn, _, err := w.(http.Hijacker).Hijack()
+			require.NoError(f.t, err)
+			conn.Close()
+			return
+		}
+		if len(f.statusErrors) > 0 {
+			code := f.statusErrors[0]
+			f.statusErrors = f.statusErrors[1:]
+			w.WriteHeader(code)
+			fmt.Fprintf(w, `{"error":{"message":"status %d"}}`, code)
+			return
+		}
+		status := JobStatus{JobID: "job-1", State: JobStateRunning}
+		switch {
+		case !f.started:
+			status.State = JobStateAccepted
+		case f.pollsLeft > 0:
+			f.pollsLeft--
+		case f.fail:
+			status.State = JobStateFailed
+			status.ErrorMessage = "bad audio"
+		default:
+			status.State = JobStateCompleted
+			for name := range f.uploads {
+				status.Tasks = append(status.Tasks, JobTaskStatus{
+					Inputs:  []JobFileRef{{FileName: name}},
+					Outputs: []JobFileRef{{FileName: name + ".json"}},
+					State:   "Success",
+				})
+			}
+		}
+		json.NewEncoder(w).Encode(status)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func TestSpeechToTextJob(t *testing.T) {
+	server := newFakeJobServer(t)
+	client := NewClient("test", WithBaseURL(server.URL))
+
+	job, err := client.CreateSpeechToTextJob(SpeechToTextParams{Language: Ptr(LanguageHindi), WithDiarization: Ptr(true)})
+	require.NoError(t, err)
+	assert.Equal(t, "job-1", job.ID)
+	assert.Equal(t, map[string]any{"language_code": "hi-IN", "with_diarization": true}, server.params)
+
+	require.NoError(t, job.Upload(
+		JobFile{Name: "a.wav", Reader: strings.NewReader("audio a")},
+		JobFile{Name: "b.wav", Reader: strings.NewReader("audio b")},
+	))
+	assert.Equal(t, map[string]string{"a.wav": "audio a", "b.wav": "audio b"}, server.uploads)
+
+	_, err = job.Start()
+	require.NoError(t, err)
+
+	var states []JobState
+	status, err := job.Wait(&JobWaitOptions{
+		PollInterval: time.Millisecond,
+		OnStatus:     func(status JobStatus) { states = append(states, status.State) },
+	})
+	require.NoError(t, err)
+	assert.Equal(t, JobStateCompleted, status.State)
+	assert.Equal(t, []JobState{JobStateRunning, JobStateRunning, JobStateCompleted}, states)
+
+	results, err := job.Results()
+	require.NoError(t, err)
+	require.Len(t, results, 2)
+	assert.Equal(t, "transcript of audio b", results["b.wav"].Transcript)
+	assert.Equal(t, LanguageHindi, results["a.wav"].Language)
+}
+
+func TestSpeechToTextJobResume(t *testing.T) {
+	server := newFakeJobServer(t)
+	server.started = true
+	server.uploads["a.wav"] = "audio a"
+	client := NewClient("test", WithBaseURL(server.URL))
+
+	// A new process only has the job ID.
+	job := client.SpeechToTextJob("job-1")
+	_, err := job.Results()
+	assert.ErrorContains(t, err, "is Running")
+
+	_, err = job.Wait(&JobWaitOptions{PollInterval: time.Millisecond})
+	require.NoError(t, err)
+	results, err := job.Results()
+	require.NoError(t, err)
+	assert.Equal(t, "transcript of audio a", results["a.wav"].Transcript)
+}
+
+func TestSpeechToTextJobFailure(t *testing.T) {
+	server := newFakeJobServer(t)
+	server.started = true
+	server.fail = true
+	client := NewClient("test", WithBaseURL(server.URL))
+
+	status, err := client.SpeechToTextJob("job-1").Wait(&JobWaitOptions{PollInterval: time.Millisecond})
+	assert.ErrorIs(t, err, ErrJobFailed)
+	assert.ErrorContains(t, err, "bad audio")
+	require.NotNil(t, status)
+	assert.Equal(t, JobStateFailed, status.State)
+}
+
+func TestSpeechToTextJobWaitRetriesTransientErrors(t *testing.T) {
+	server := newFakeJobServer(t)
+	server.started = true
+	server.pollsLeft = 0
+	server.statusErrors = []int{http.StatusServiceUnavailable, http.StatusTooManyRequests}
+	client := NewClient("test", WithBaseURL(server.URL))
+
+	status, err := client.SpeechToTextJob("job-1").Wait(&JobWaitOptions{PollInterval: time.Millisecond})
+	require.NoError(t, err)
+	assert.Equal(t, JobStateCompleted, status.State)
+	assert.Equal(t, 3, server.polls)
+
+	// So do dropped connections. A new transport is used, since one reusing a pooled
+	// connection would resend the request itself.
+	server.polls = 0
+	server.dropPolls = 1
+	fresh := NewClient("test", WithBaseURL(server.URL), WithTransport(&http.Transport{}))
+	status, err = fresh.SpeechToTextJob("job-1").Wait(&JobWaitOptions{PollInterval: time.Millisecond})
+	require.NoError(t, err)
+	assert.Equal(t, JobStateCompleted, status.State)
+	assert.Equal(t, 2, server.polls)
+
+	// Permanent errors end the wait at once.
+	server.polls = 0
+	server.statusErrors = []int{http.StatusNotFound}
+	_, err = client.SpeechToTextJob("job-1").Wait(&JobWaitOptions{PollInterval: time.Millisecond})
+	assert.ErrorIs(t, err, ErrNotFound)
+	assert.Equal(t, 1, server.polls)
+}
+
+func TestSpeechToTextJobWaitBackoff(t *testing.T) {
+	server := newFakeJobServer(t)
+	server.started = true
+	server.pollsLeft = 1000
+	client := NewClient("test", WithBaseURL(server.URL))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	_, err := client.SpeechToTextJob("job-1").WaitWithContext(ctx, &JobWaitOptions{PollInterval: 10 * time.Millisecond, MaxPollInterval: time.Second})
+	assert.ErrorIs(t, err, ErrRequestCanceled)
+
+	// With the interval growing by half each time, only a handful of polls fit in the deadline.
+	assert.Less(t, server.polls, 8, fmt.Sprintf("polled %d times", server.polls))
+}
@@ -1,15 +1,19 @@
 package sarvam
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"net/http"
 )
 
 // Message represents a message in the chat conversation.
 type Message struct {
-	Role    string `json:"role"`
-	Content string `json:"content"`
+	Role       string     `json:"role"`
+	Content    string     `json:"content"`
+	ToolCalls  []ToolCall `json:"tool_calls,omitempty"`   // Tool calls requested by the assistant
+	ToolCallID string     `json:"tool_call_id,omitempty"` // Tool call answered by a tool message
 }
 
 type MessageRole string
@@ -18,6 +22,7 @@ const (
 	MessageRoleSystem    MessageRole = "system"
 	MessageRoleUser      MessageRole = "user"
 	MessageRoleAssistant MessageRole = "assistant"
+	MessageRoleTool      MessageRole = "tool"
 )
 
 func NewMessage(role MessageRole, content string) Message {
@@ -39,6 +44,15 @@ func NewAssistantMessage(content string) Message {
 	return NewMessage(MessageRoleAssistant, content)
 }
 
+// NewToolMessage creates a message carrying the result of the tool call with the given ID.
+func NewToolMessage(toolCallID string, content string) Message {
+	return Message{
+		Role:       string(MessageRoleTool),
+		Content:    content,
+		ToolCallID: toolCallID,
+	}
+}
+
 // ReasoningEffort represents the reasoning effort level for chat completions.
 type ReasoningEffort string
 
@@ -54,20 +68,49 @@ type ChatCompletionParams struct {
 	TopP             *float64
 	ReasoningEffort  *ReasoningEffort
 	MaxTokens        *int
-	Stream           *bool
+	Stream           *bool    // Must be nil or false for ChatCompletion; use ChatCompletionStream for streaming.
 	Stop             []string // string or []string. TODO: Find a way to make this more type safe.
 	N                *int
 	Seed             *int64
 	FrequencyPenalty *float64
 	PresencePenalty  *float64
 	WikiGrounding    *bool
+	Tools            []Tool      // Functions the model may call
+	ToolChoice       *ToolChoice // Controls whether and which tools are called
+	ResponseFormat   *ResponseFormat
 }
 
 // ChatCompletionChoice represents a single completion choice.
+//
+// When sarvam-m reasons before answering, the <think> block it produces is moved from
+// the message content to Reasoning, leaving only the answer in Message.Content. Reasoning
+// that lacks the opening tag is only recognised in replies to requests that set
+// ReasoningEffort.
 type ChatCompletionChoice struct {
 	FinishReason string  `json:"finish_reason"`
 	Index        int     `json:"index"`
 	Message      Message `json:"message"`
+	Reasoning    string  `json:"reasoning,omitempty"`
+}
+
+// UnmarshalJSON implements json.Unmarshaler for ChatCompletionChoice, separating a
+// reasoning block that opens with a <think> tag from the message content.
+func (c *ChatCompletionChoice) UnmarshalJSON(data []byte) error {
+	type choice ChatCompletionChoice
+	if err := json.Unmarshal(data, (*choice)(c)); err != nil {
+		return err
+	}
+	c.splitReasoning(c.Message.Content, false)
+	return nil
+}
+
+// splitReasoning separates the reasoning in content, the message content as received,
+// from the answer, as splitReasoning does.
+func (c *ChatCompletionChoice) splitReasoning(content string, untagged bool) {
+	if reasoning, answer := splitReasoning(content, untagged); reasoning != "" || answer != content {
+		c.Reasoning = reasoning
+		c.Message.Content = answer
+	}
 }
 
 // Usage represents token usage information for the API call.
@@ -89,32 +132,94 @@ type ChatCompletionResponse struct {
 
 // ChatCompletion creates a chat completion using the Sarvam AI API.
 func (c *Client) ChatCompletion(messages []Message, model ChatCompletionModel, req *ChatCompletionParams) (*ChatCompletionResponse, error) {
+	return c.ChatCompletionWithContext(context.Background(), messages, model, req)
+}
+
+// ChatCompletionWithContext is like ChatCompletion but uses ctx to control cancellation and deadlines.
+func (c *Client) ChatCompletionWithContext(ctx context.Context, messages []Message, model ChatCompletionModel, req *ChatCompletionParams) (*ChatCompletionResponse, error) {
+	if req != nil && req.Stream != nil && *req.Stream {
+		return nil, fmt.Errorf("stream is not supported by ChatCompletion, use ChatCompletionStream instead")
+	}
+
+	payload, err := newChatCompletionRequest(messages, model, req)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := c.makeJsonHTTPRequest(ctx, http.MethodPost, c.baseURL+EndpointChatCompletions, payload)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, parseAPIError(resp)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	var response ChatCompletionResponse
+	if err := json.Unmarshal(body, &response); err != nil {
+		return nil, err
+	}
+
+	if req != nil && req.ReasoningEffort != nil {
+		// Reasoning was requested, so a closing tag without an opening one ends it too.
+		// Decoding the response only split off blocks with both tags.
+		var received struct {
+			Choices []struct {
+				Message struct {
+					Content string `json:"content"`
+				} `json:"message"`
+			} `json:"choices"`
+		}
+		if err := json.Unmarshal(body, &received); err != nil {
+			return nil, err
+		}
+		for i := range response.Choices {
+			response.Choices[i].splitReasoning(received.Choices[i].Message.Content, true)
+		}
+	}
+
+	return &response, nil
+}
+
+// chatCompletionRequest is the request body of the chat completions API.
+type chatCompletionRequest struct {
+	Model            ChatCompletionModel `json:"model"`
+	Messages         []Message           `json:"messages"`
+	Temperature      *float64            `json:"temperature,omitempty"`
+	TopP             *float64            `json:"top_p,omitempty"`
+	ReasoningEffort  *ReasoningEffort    `json:"reasoning_effort,omitempty"`
+	MaxTokens        *int                `json:"max_tokens,omitempty"`
+	Stream           *bool               `json:"stream,omitempty"`
+	Stop             interface{}         `json:"stop,omitempty"` // string or []string. TODO: Find a way to make this more type safe.
+	N                *int                `json:"n,omitempty"`
+	Seed             *int64              `json:"seed,omitempty"`
+	FrequencyPenalty *float64            `json:"frequency_penalty,omitempty"`
+	PresencePenalty  *float64            `json:"presence_penalty,omitempty"`
+	WikiGrounding    *bool               `json:"wiki_grounding,omitempty"`
+	Tools            []Tool              `json:"tools,omitempty"`
+	ToolChoice       *ToolChoice         `json:"tool_choice,omitempty"`
+	ResponseFormat   *ResponseFormat     `json:"response_format,omitempty"`
+}
+
+// newChatCompletionRequest validates the arguments of a chat completion and builds its request body.
+func newChatCompletionRequest(messages []Message, model ChatCompletionModel, req *ChatCompletionParams) (chatCompletionRequest, error) {
+	var payload chatCompletionRequest
 	if len(messages) == 0 {
-		return nil, fmt.Errorf("messages cannot be empty")
+		return payload, fmt.Errorf("messages cannot be empty")
 	}
 
 	if model == "" {
-		return nil, fmt.Errorf("model is required")
-	}
-	// TODO: Include constraints as per the API docs
-
-	type chatCompletionRequest struct {
-		Model            ChatCompletionModel `json:"model"`
-		Messages         []Message           `json:"messages"`
-		Temperature      *float64            `json:"temperature,omitempty"`
-		TopP             *float64            `json:"top_p,omitempty"`
-		ReasoningEffort  *ReasoningEffort    `json:"reasoning_effort,omitempty"`
-		MaxTokens        *int                `json:"max_tokens,omitempty"`
-		Stream           *bool               `json:"stream,omitempty"`
-		Stop             interface{}         `json:"stop,omitempty"` // string or []string. TODO: Find a way to make this more type safe.
-		N                *int                `json:"n,omitempty"`
-		Seed             *int64              `json:"seed,omitempty"`
-		FrequencyPenalty *float64            `json:"frequency_penalty,omitempty"`
-		PresencePenalty  *float64            `json:"presence_penalty,omitempty"`
-		WikiGrounding    *bool               `json:"wiki_grounding,omitempty"`
+		return payload, fmt.Errorf("model is required")
+	}
+	if _, _, err := resolveModel(EndpointChatCompletions, string(model)); err != nil {
+		return payload, err
 	}
 
-	var payload chatCompletionRequest
 	payload.Model = model
 	payload.Messages = messages
 
@@ -156,24 +261,18 @@ func (c *Client) ChatCompletion(messages []Message, model ChatCompletionModel, r
 		if req.WikiGrounding != nil {
 			payload.WikiGrounding = req.WikiGrounding
 		}
+		if len(req.Tools) > 0 {
+			payload.Tools = req.Tools
+		}
+		if req.ToolChoice != nil {
+			payload.ToolChoice = req.ToolChoice
+		}
+		if req.ResponseFormat != nil {
+			payload.ResponseFormat = req.ResponseFormat
+		}
 	}
 
-	resp, err := c.makeJsonHTTPRequest(http.MethodPost, c.baseURL+"/v1/chat/completions", payload)
-	if err != nil {
-		return nil, err
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		return nil, parseAPIError(resp)
-	}
-
-	var response ChatCompletionResponse
-	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
-		return nil, err
-	}
-
-	return &response, nil
+	return payload, nil
 }
 
 // GetFirstChoiceContent returns the content of the first choice from the response.
@@ -184,6 +283,14 @@ func (r *ChatCompletionResponse) GetFirstChoiceContent() string {
 	return ""
 }
 
+// GetFirstChoiceReasoning returns the reasoning of the first choice from the response.
+func (r *ChatCompletionResponse) GetFirstChoiceReasoning() string {
+	if len(r.Choices) > 0 {
+		return r.Choices[0].Reasoning
+	}
+	return ""
+}
+
 // GetChoiceContent returns the content of a specific choice by index.
 func (r *ChatCompletionResponse) GetChoiceContent(index int) string {
 	if index >= 0 && index < len(r.Choices) {
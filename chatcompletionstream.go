@@ -0,0 +1,364 @@
+package sarvam
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"iter"
+	"net/http"
+	"slices"
+	"strings"
+)
+
+// ChatCompletionDelta is the part of a message produced since the previous chunk of a stream.
+// Text inside the reasoning block of the reply is delivered in Reasoning rather than Content.
+type ChatCompletionDelta struct {
+	Role      string          `json:"role,omitempty"`
+	Content   string          `json:"content,omitempty"`
+	Reasoning string          `json:"reasoning,omitempty"`
+	ToolCalls []ToolCallDelta `json:"tool_calls,omitempty"`
+}
+
+// ToolCallDelta is the part of a tool call produced since the previous chunk of a stream.
+// Fragments of the same call share an Index; the ID and function name are only sent once.
+type ToolCallDelta struct {
+	Index    int          `json:"index"`
+	ID       string       `json:"id,omitempty"`
+	Type     string       `json:"type,omitempty"`
+	Function FunctionCall `json:"function"`
+}
+
+// ChatCompletionChunkChoice represents the change to a single completion choice within a chunk.
+type ChatCompletionChunkChoice struct {
+	Index        int                 `json:"index"`
+	Delta        ChatCompletionDelta `json:"delta"`
+	FinishReason *string             `json:"finish_reason"`
+}
+
+// ChatCompletionChunk represents a single server-sent event of a streamed chat completion.
+// Usage is only set on the final chunk, if at all.
+type ChatCompletionChunk struct {
+	ID      string                      `json:"id"`
+	Choices []ChatCompletionChunkChoice `json:"choices"`
+	Created int64                       `json:"created"`
+	Model   string                      `json:"model"`
+	Object  string                      `json:"object"`
+	Usage   *Usage                      `json:"usage,omitempty"`
+}
+
+// ChatCompletionStream reads the chunks of a streamed chat completion.
+// It must be closed once it is no longer needed.
+type ChatCompletionStream struct {
+	ctx       context.Context
+	resp      *http.Response
+	reader    *bufio.Reader
+	err       error
+	splitters map[int]*reasoningSplitter
+
+	// holdUntagged is set when reasoning was requested, so that a reply whose reasoning
+	// lacks the opening <think> tag is recognised, at the cost of not streaming replies
+	// without that tag until they end or the closing tag arrives.
+	holdUntagged bool
+}
+
+// ChatCompletionStream creates a chat completion and streams the response as it is generated.
+// The Stream field of req is ignored.
+//
+// If req sets ReasoningEffort, a reply that does not open with a <think> tag is held back
+// until its closing tag arrives or the reply ends, since its reasoning may lack the opening tag.
+func (c *Client) ChatCompletionStream(messages []Message, model ChatCompletionModel, req *ChatCompletionParams) (*ChatCompletionStream, error) {
+	return c.ChatCompletionStreamWithContext(context.Background(), messages, model, req)
+}
+
+// ChatCompletionStreamWithContext is like ChatCompletionStream but uses ctx to control cancellation
+// and deadlines. Canceling ctx also aborts a stream that is being read.
+func (c *Client) ChatCompletionStreamWithContext(ctx context.Context, messages []Message, model ChatCompletionModel, req *ChatCompletionParams) (*ChatCompletionStream, error) {
+	payload, err := newChatCompletionRequest(messages, model, req)
+	if err != nil {
+		return nil, err
+	}
+	payload.Stream = Ptr(true)
+
+	resp, err := c.makeJsonHTTPRequest(ctx, http.MethodPost, c.baseURL+EndpointChatCompletions, payload)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		defer resp.Body.Close()
+		return nil, parseAPIError(resp)
+	}
+
+	stream := newChatCompletionStream(ctx, resp)
+	stream.holdUntagged = req != nil && req.ReasoningEffort != nil
+	return stream, nil
+}
+
+// NewChatCompletionStream returns a stream reading the server-sent events of a chat completion
+// from body, such as a recorded response or one produced by a fake. Closing the stream closes body.
+func NewChatCompletionStream(ctx context.Context, body io.ReadCloser) *ChatCompletionStream {
+	return newChatCompletionStream(ctx, &http.Response{StatusCode: http.StatusOK, Body: body})
+}
+
+func newChatCompletionStream(ctx context.Context, resp *http.Response) *ChatCompletionStream {
+	return &ChatCompletionStream{
+		ctx:       ctx,
+		resp:      resp,
+		reader:    bufio.NewReader(resp.Body),
+		splitters: make(map[int]*reasoningSplitter),
+	}
+}
+
+// Recv returns the next chunk of the stream. It returns io.EOF once the stream is complete.
+func (s *ChatCompletionStream) Recv() (*ChatCompletionChunk, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+
+	chunk, err := s.next()
+	if errors.Is(err, io.EOF) {
+		if chunk := s.flush(); chunk != nil {
+			return chunk, nil
+		}
+	}
+	if err != nil {
+		if !errors.Is(err, io.EOF) {
+			err = contextError(s.ctx, err)
+		}
+		s.err = err
+		return nil, err
+	}
+	return chunk, nil
+}
+
+// All returns an iterator over the remaining chunks of the stream. Iteration stops after
+// the first error, which is yielded along with a nil chunk; the end of the stream is not
+// reported as an error.
+func (s *ChatCompletionStream) All() iter.Seq2[*ChatCompletionChunk, error] {
+	return func(yield func(*ChatCompletionChunk, error) bool) {
+		for {
+			chunk, err := s.Recv()
+			if errors.Is(err, io.EOF) {
+				return
+			}
+			if !yield(chunk, err) || err != nil {
+				return
+			}
+		}
+	}
+}
+
+// Close releases the connection of the stream. Chunks that have not been read are discarded.
+func (s *ChatCompletionStream) Close() error {
+	if s.err == nil {
+		s.err = errors.New("stream is closed")
+	}
+	return s.resp.Body.Close()
+}
+
+// Collect reads the remaining chunks of the stream and accumulates them into a response.
+func (s *ChatCompletionStream) Collect() (*ChatCompletionResponse, error) {
+	var acc ChatCompletionAccumulator
+	for chunk, err := range s.All() {
+		if err != nil {
+			return nil, err
+		}
+		if err := acc.Add(chunk); err != nil {
+			return nil, err
+		}
+	}
+	return acc.Response(), nil
+}
+
+// next reads server-sent events until one carries a chunk.
+func (s *ChatCompletionStream) next() (*ChatCompletionChunk, error) {
+	for {
+		data, err := readServerSentEvent(s.reader)
+		if err != nil {
+			return nil, err
+		}
+		if len(data) == 0 {
+			continue
+		}
+		if bytes.Equal(data, []byte("[DONE]")) {
+			return nil, io.EOF
+		}
+
+		var event struct {
+			ChatCompletionChunk
+			Error *struct {
+				Message   string `json:"message"`
+				Code      string `json:"code"`
+				RequestID string `json:"request_id"`
+			} `json:"error"`
+		}
+		if err := json.Unmarshal(data, &event); err != nil {
+			return nil, fmt.Errorf("failed to decode stream chunk: %w", err)
+		}
+		if event.Error != nil {
+			return nil, &HTTPError{
+				StatusCode: s.resp.StatusCode,
+				Message:    event.Error.Message,
+				Code:       event.Error.Code,
+				RequestID:  event.Error.RequestID,
+			}
+		}
+		s.splitReasoning(&event.ChatCompletionChunk)
+		return &event.ChatCompletionChunk, nil
+	}
+}
+
+// splitReasoning moves the reasoning text in the deltas of chunk from Content to Reasoning.
+func (s *ChatCompletionStream) splitReasoning(chunk *ChatCompletionChunk) {
+	for i := range chunk.Choices {
+		choice := &chunk.Choices[i]
+		splitter, ok := s.splitters[choice.Index]
+		if !ok {
+			splitter = &reasoningSplitter{holdUntagged: s.holdUntagged}
+			s.splitters[choice.Index] = splitter
+		}
+
+		reasoning, answer := splitter.write(choice.Delta.Content)
+		if choice.FinishReason != nil {
+			flushedReasoning, flushedAnswer := splitter.flush()
+			reasoning += flushedReasoning
+			answer += flushedAnswer
+		}
+		choice.Delta.Reasoning += reasoning
+		choice.Delta.Content = answer
+	}
+}
+
+// flush returns a chunk with the text still held back for choices whose stream ended
+// without a finish reason, or nil if there is none.
+func (s *ChatCompletionStream) flush() *ChatCompletionChunk {
+	var chunk ChatCompletionChunk
+	for index, splitter := range s.splitters {
+		reasoning, answer := splitter.flush()
+		if reasoning != "" || answer != "" {
+			chunk.Choices = append(chunk.Choices, ChatCompletionChunkChoice{
+				Index: index,
+				Delta: ChatCompletionDelta{Reasoning: reasoning, Content: answer},
+			})
+		}
+	}
+	if len(chunk.Choices) == 0 {
+		return nil
+	}
+	slices.SortFunc(chunk.Choices, func(a, b ChatCompletionChunkChoice) int {
+		return a.Index - b.Index
+	})
+	return &chunk
+}
+
+// readServerSentEvent reads a single server-sent event and returns its data, joining
+// multiple data lines with newlines. Other fields and comments are ignored.
+// It returns io.EOF if the stream ends before an event is complete.
+func readServerSentEvent(r *bufio.Reader) ([]byte, error) {
+	var data []byte
+	seenData := false
+	for {
+		line, err := r.ReadBytes('\n')
+		line = bytes.TrimRight(line, "\r\n")
+
+		if len(line) == 0 && seenData {
+			return data, nil
+		}
+		field, value, _ := bytes.Cut(line, []byte(":"))
+		if bytes.Equal(field, []byte("data")) {
+			value = bytes.TrimPrefix(value, []byte(" "))
+			if seenData {
+				data = append(data, '\n')
+			}
+			data = append(data, value...)
+			seenData = true
+		}
+
+		if err != nil {
+			if errors.Is(err, io.EOF) && seenData {
+				return data, nil
+			}
+			return nil, err
+		}
+	}
+}
+
+// ChatCompletionAccumulator merges the chunks of a streamed chat completion into a
+// single response. The zero value is ready to use.
+type ChatCompletionAccumulator struct {
+	response ChatCompletionResponse
+	choices  map[int]*ChatCompletionChoice
+}
+
+// Add merges chunk into the accumulated response. It fails if a tool call delta has an
+// index that neither continues an earlier tool call nor starts the next one.
+func (a *ChatCompletionAccumulator) Add(chunk *ChatCompletionChunk) error {
+	if a.choices == nil {
+		a.choices = make(map[int]*ChatCompletionChoice)
+	}
+	if chunk.ID != "" {
+		a.response.ID = chunk.ID
+	}
+	if chunk.Created != 0 {
+		a.response.Created = chunk.Created
+	}
+	if chunk.Model != "" {
+		a.response.Model = chunk.Model
+	}
+	if chunk.Usage != nil {
+		a.response.Usage = chunk.Usage
+	}
+	a.response.Object = "chat.completion"
+
+	for _, delta := range chunk.Choices {
+		choice, ok := a.choices[delta.Index]
+		if !ok {
+			choice = &ChatCompletionChoice{Index: delta.Index, Message: Message{Role: string(MessageRoleAssistant)}}
+			a.choices[delta.Index] = choice
+		}
+		if delta.Delta.Role != "" {
+			choice.Message.Role = delta.Delta.Role
+		}
+		choice.Message.Content += delta.Delta.Content
+		choice.Reasoning += delta.Delta.Reasoning
+		for _, call := range delta.Delta.ToolCalls {
+			if call.Index < 0 || call.Index > len(choice.Message.ToolCalls) {
+				return fmt.Errorf("invalid tool call index %d in choice %d", call.Index, delta.Index)
+			}
+			if call.Index == len(choice.Message.ToolCalls) {
+				choice.Message.ToolCalls = append(choice.Message.ToolCalls, ToolCall{Type: "function"})
+			}
+			toolCall := &choice.Message.ToolCalls[call.Index]
+			if call.ID != "" {
+				toolCall.ID = call.ID
+			}
+			if call.Type != "" {
+				toolCall.Type = call.Type
+			}
+			toolCall.Function.Name += call.Function.Name
+			toolCall.Function.Arguments += call.Function.Arguments
+		}
+		if delta.FinishReason != nil {
+			choice.FinishReason = *delta.FinishReason
+		}
+	}
+	return nil
+}
+
+// Response returns the response accumulated so far.
+func (a *ChatCompletionAccumulator) Response() *ChatCompletionResponse {
+	response := a.response
+	response.Choices = make([]ChatCompletionChoice, 0, len(a.choices))
+	for _, choice := range a.choices {
+		choice := *choice
+		choice.Reasoning = strings.TrimSpace(choice.Reasoning)
+		response.Choices = append(response.Choices, choice)
+	}
+	slices.SortFunc(response.Choices, func(a, b ChatCompletionChoice) int {
+		return a.Index - b.Index
+	})
+	return &response
+}
@@ -0,0 +1,185 @@
+package sarvam
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newStreamServer(t *testing.T, events ...string) *httptest.Server {
+	t.Helper()
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]any
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		assert.Equal(t, true, body["stream"])
+
+		w.Header().Set("Content-Type", "text/event-stream")
+		for _, event := range events {
+			fmt.Fprintf(w, "%s\n\n", event)
+			w.(http.Flusher).Flush()
+		}
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+func TestChatCompletionStream(t *testing.T) {
+	httpTestServer := newStreamServer(t,
+		`: keep-alive`,
+		`data: {"id":"c1","model":"sarvam-m","created":1,"choices":[{"index":0,"delta":{"role":"assistant"}}]}`,
+		`data: {"id":"c1","choices":[{"index":0,"delta":{"content":"Hello"}}]}`,
+		`data: {"id":"c1","choices":[{"index":0,"delta":{"content":", world"},"finish_reason":"stop"}]}`,
+		`data: {"id":"c1","choices":[],"usage":{"prompt_tokens":3,"completion_tokens":2,"total_tokens":5}}`,
+		`data: [DONE]`,
+	)
+
+	client := NewClient("test", WithBaseURL(httpTestServer.URL))
+	stream, err := client.ChatCompletionStream([]Message{NewUserMessage("Hi")}, ChatCompletionModelSarvamM, nil)
+	require.NoError(t, err)
+	defer stream.Close()
+
+	var content strings.Builder
+	var finishReason string
+	for chunk, err := range stream.All() {
+		require.NoError(t, err)
+		for _, choice := range chunk.Choices {
+			content.WriteString(choice.Delta.Content)
+			if choice.FinishReason != nil {
+				finishReason = *choice.FinishReason
+			}
+		}
+	}
+	assert.Equal(t, "Hello, world", content.String())
+	assert.Equal(t, "stop", finishReason)
+
+	_, err = stream.Recv()
+	assert.ErrorIs(t, err, io.EOF)
+}
+
+func TestChatCompletionStreamCollect(t *testing.T) {
+	httpTestServer := newStreamServer(t,
+		`data: {"id":"c1","model":"sarvam-m","created":1,"choices":[{"index":1,"delta":{"role":"assistant","content":"B"}},{"index":0,"delta":{"role":"assistant","content":"A"}}]}`,
+		`data: {"id":"c1","choices":[{"index":0,"delta":{"content":"1"},"finish_reason":"stop"},{"index":1,"delta":{"content":"2"},"finish_reason":"length"}],"usage":{"prompt_tokens":3,"completion_tokens":4,"total_tokens":7}}`,
+		`data: [DONE]`,
+	)
+
+	client := NewClient("test", WithBaseURL(httpTestServer.URL))
+	stream, err := client.ChatCompletionStream([]Message{NewUserMessage("Hi")}, ChatCompletionModelSarvamM, &ChatCompletionParams{N: Ptr(2)})
+	require.NoError(t, err)
+	defer stream.Close()
+
+	response, err := stream.Collect()
+	require.NoError(t, err)
+	assert.Equal(t, "c1", response.ID)
+	assert.Equal(t, "sarvam-m", response.Model)
+	require.Len(t, response.Choices, 2)
+	assert.Equal(t, "A1", response.GetChoiceContent(0))
+	assert.Equal(t, "B2", response.GetChoiceContent(1))
+	assert.Equal(t, "stop", response.Choices[0].FinishReason)
+	assert.Equal(t, "length", response.Choices[1].FinishReason)
+	assert.Equal(t, 7, response.Usage.TotalTokens)
+}
+
+func TestChatCompletionStreamErrorEvent(t *testing.T) {
+	httpTestServer := newStreamServer(t,
+		`data: {"id":"c1","choices":[{"index":0,"delta":{"content":"Hel"}}]}`,
+		`data: {"error":{"message":"model overloaded","code":"internal_server_error"}}`,
+	)
+
+	client := NewClient("test", WithBaseURL(httpTestServer.URL))
+	stream, err := client.ChatCompletionStream([]Message{NewUserMessage("Hi")}, ChatCompletionModelSarvamM, nil)
+	require.NoError(t, err)
+	defer stream.Close()
+
+	_, err = stream.Recv()
+	require.NoError(t, err)
+
+	_, err = stream.Recv()
+	var httpErr *HTTPError
+	require.ErrorAs(t, err, &httpErr)
+	assert.Equal(t, "model overloaded", httpErr.Message)
+}
+
+func TestChatCompletionStreamHTTPError(t *testing.T) {
+	httpTestServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+		w.Write([]byte(`{"error":{"message":"invalid key"}}`))
+	}))
+	defer httpTestServer.Close()
+
+	client := NewClient("test", WithBaseURL(httpTestServer.URL))
+	_, err := client.ChatCompletionStream([]Message{NewUserMessage("Hi")}, ChatCompletionModelSarvamM, nil)
+	var httpErr *HTTPError
+	require.ErrorAs(t, err, &httpErr)
+	assert.Equal(t, http.StatusUnauthorized, httpErr.StatusCode)
+}
+
+func TestChatCompletionStreamCancel(t *testing.T) {
+	done := make(chan struct{})
+	httpTestServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "data: {\"choices\":[{\"index\":0,\"delta\":{\"content\":\"a\"}}]}\n\n")
+		w.(http.Flusher).Flush()
+		select {
+		case <-r.Context().Done():
+		case <-done:
+		}
+	}))
+	defer httpTestServer.Close()
+	defer close(done)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	client := NewClient("test", WithBaseURL(httpTestServer.URL))
+	stream, err := client.ChatCompletionStreamWithContext(ctx, []Message{NewUserMessage("Hi")}, ChatCompletionModelSarvamM, nil)
+	require.NoError(t, err)
+	defer stream.Close()
+
+	_, err = stream.Recv()
+	require.NoError(t, err)
+
+	time.AfterFunc(20*time.Millisecond, cancel)
+	_, err = stream.Recv()
+	assert.ErrorIs(t, err, ErrRequestCanceled)
+}
+
+func TestChatCompletionRejectsStream(t *testing.T) {
+	client := NewClient("test")
+	_, err := client.ChatCompletion([]Message{NewUserMessage("Hi")}, ChatCompletionModelSarvamM, &ChatCompletionParams{Stream: Ptr(true)})
+	assert.ErrorContains(t, err, "ChatCompletionStream")
+}
+
+func TestReadServerSentEvent(t *testing.T) {
+	r := bufio.NewReader(strings.NewReader("event: message\r\ndata: line one\r\ndata: line two\r\n\r\n: comment\n\ndata:{\"a\":1}"))
+
+	data, err := readServerSentEvent(r)
+	require.NoError(t, err)
+	assert.Equal(t, "line one\nline two", string(data))
+
+	data, err = readServerSentEvent(r)
+	require.NoError(t, err)
+	assert.Equal(t, `{"a":1}`, string(data))
+
+	_, err = readServerSentEvent(r)
+	assert.ErrorIs(t, err, io.EOF)
+}
+
+func TestNewChatCompletionStream(t *testing.T) {
+	body := io.NopCloser(strings.NewReader("data: {\"id\":\"c1\",\"choices\":[{\"index\":0,\"delta\":{\"role\":\"assistant\",\"content\":\"Hi\"},\"finish_reason\":\"stop\"}]}\n\ndata: [DONE]\n\n"))
+	stream := NewChatCompletionStream(context.Background(), body)
+	defer stream.Close()
+
+	response, err := stream.Collect()
+	require.NoError(t, err)
+	require.Len(t, response.Choices, 1)
+	assert.Equal(t, "Hi", response.Choices[0].Message.Content)
+	assert.Equal(t, "stop", response.Choices[0].FinishReason)
+}
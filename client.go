@@ -3,72 +3,190 @@ package sarvam
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"mime/multipart"
 	"net/http"
 	"os"
+	"strconv"
+	"time"
+)
+
+const (
+	defaultBaseURL   = "https://api.sarvam.ai"
+	defaultUserAgent = "sarvam-go"
+)
+
+// API endpoint paths, relative to the base URL.
+const (
+	EndpointTranslate             = "/translate"
+	EndpointTextLID               = "/text-lid"
+	EndpointTransliterate         = "/transliterate"
+	EndpointTextToSpeech          = "/text-to-speech"
+	EndpointTextToSpeechStream    = "/text-to-speech/stream"
+	EndpointSpeechToText          = "/speech-to-text"
+	EndpointSpeechToTextTranslate = "/speech-to-text-translate"
+	EndpointChatCompletions       = "/v1/chat/completions"
+	EndpointSpeechToTextStream    = "/speech-to-text/ws"
+	EndpointSpeechToTextJob       = "/speech-to-text/job/v1"
 )
 
 // Client represents a Sarvam AI API client.
 type Client struct {
-	baseURL string
-	apiKey  string
+	baseURL    string
+	apiKey     string
+	httpClient *http.Client
+	transport  http.RoundTripper
+	timeout    time.Duration
+	userAgent  string
+	headers    http.Header
+
+	retryPolicy        RetryPolicy
+	rateLimiters       map[string]*rateLimiter
+	defaultRateLimiter *rateLimiter
 }
 
-// NewClient creates a new Sarvam AI client with the provided API key.
-func NewClient(apiKey string) *Client {
-	const baseURL = "https://api.sarvam.ai"
-	return &Client{apiKey: apiKey, baseURL: baseURL}
+// NewClient creates a new Sarvam AI client with the provided API key and options.
+func NewClient(apiKey string, opts ...Option) *Client {
+	c := &Client{
+		apiKey:     apiKey,
+		baseURL:    defaultBaseURL,
+		httpClient: http.DefaultClient,
+		userAgent:  defaultUserAgent,
+		headers:    make(http.Header),
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	// Apply the transport and timeout to a copy so that a shared client is never mutated.
+	if c.transport != nil || c.timeout > 0 {
+		httpClient := *c.httpClient
+		if c.transport != nil {
+			httpClient.Transport = c.transport
+		}
+		if c.timeout > 0 {
+			httpClient.Timeout = c.timeout
+		}
+		c.httpClient = &httpClient
+	}
+	return c
 }
 
 // SetBaseURL allows customization of the API endpoint URL.
+//
+// Deprecated: Use WithBaseURL when creating the client.
 func (c *Client) SetBaseURL(baseURL string) {
 	c.baseURL = baseURL
 }
 
+// ErrRequestCanceled is returned when a request is abandoned because its context
+// was canceled or its deadline expired. The context's own error is wrapped as well,
+// so errors.Is also matches context.Canceled or context.DeadlineExceeded.
+var ErrRequestCanceled = errors.New("request canceled")
+
 // makeJsonHTTPRequest sends a JSON HTTP request to the Sarvam AI API.
-func (c *Client) makeJsonHTTPRequest(method, url string, body any) (*http.Response, error) {
+func (c *Client) makeJsonHTTPRequest(ctx context.Context, method, url string, body any) (*http.Response, error) {
 	jsonBody, err := json.Marshal(body)
 	if err != nil {
 		return nil, err
 	}
 
-	bodyBytes := bytes.NewBuffer(jsonBody)
-	return c.makeHTTPRequest(method, url, bodyBytes, "application/json")
+	return c.makeHTTPRequest(ctx, method, url, jsonBody, "application/json")
+}
 
+// makeHTTPRequest sends an HTTP request to the Sarvam AI API, retrying it according to the
+// client's retry policy. The body is kept as a byte slice so that it can be replayed on every attempt.
+func (c *Client) makeHTTPRequest(ctx context.Context, method, url string, body []byte, contentType string) (*http.Response, error) {
+	for attempt := 1; ; attempt++ {
+		resp, err := c.doHTTPRequest(ctx, method, url, body, contentType)
+		if attempt >= c.retryPolicy.MaxAttempts || !c.retryPolicy.shouldRetry(resp, err) {
+			return resp, err
+		}
+
+		delay := c.retryPolicy.backoff(attempt, resp)
+		discardBody(resp)
+		if err := sleep(ctx, delay); err != nil {
+			return nil, contextError(ctx, err)
+		}
+	}
 }
 
-// makeHTTPRequest sends an HTTP request to the Sarvam AI API.
-func (c *Client) makeHTTPRequest(method, url string, body *bytes.Buffer, contentType string) (*http.Response, error) {
-	req, err := http.NewRequest(method, url, body)
+// doHTTPRequest sends a single HTTP request to the Sarvam AI API, waiting for the
+// endpoint's rate limiter if one is configured.
+func (c *Client) doHTTPRequest(ctx context.Context, method, url string, body []byte, contentType string) (*http.Response, error) {
+	release := func() {}
+	if limiter := c.rateLimiterFor(url); limiter != nil {
+		var err error
+		release, err = limiter.acquire(ctx)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, url, bytes.NewReader(body))
 	if err != nil {
-		return nil, err
+		release()
+		return nil, fmt.Errorf("%w: %w", errBuildRequest, err)
 	}
 
-	req.Header.Set("Content-Type", contentType)
+	for key, values := range c.headers {
+		req.Header[key] = append([]string(nil), values...)
+	}
+	if c.userAgent != "" {
+		req.Header.Set("User-Agent", c.userAgent)
+	}
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
 	req.Header.Set("api-subscription-key", c.apiKey)
 
-	return http.DefaultClient.Do(req)
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		release()
+		return nil, contextError(ctx, err)
+	}
+	holdUntilClosed(resp, release)
+	return resp, nil
+}
+
+// contextError returns an ErrRequestCanceled error if ctx is done, and err otherwise.
+func contextError(ctx context.Context, err error) error {
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		return fmt.Errorf("%w: %w", ErrRequestCanceled, ctxErr)
+	}
+	return err
 }
 
 // buildSpeechToTextRequest builds a multipart form request for speech-to-text.
-func (c *Client) buildSpeechToTextRequest(endpoint string, speech io.Reader, params SpeechToTextParams) (*http.Response, error) {
+func (c *Client) buildSpeechToTextRequest(ctx context.Context, endpoint string, speech io.Reader, params SpeechToTextParams) (*http.Response, error) {
+	if params.NumSpeakers != nil && *params.NumSpeakers < 1 {
+		return nil, fmt.Errorf("num_speakers must be at least 1, got %d", *params.NumSpeakers)
+	}
+	if err := validateSpeechToTextModel(endpoint, modelID(params.Model), params.Language); err != nil {
+		return nil, err
+	}
+
+	format, speech, err := detectAudioFormat(speech, params.AudioCodec)
+	if err != nil {
+		return nil, err
+	}
 
 	// Create a buffer to store the multipart form data
 	var requestBody bytes.Buffer
 	writer := multipart.NewWriter(&requestBody)
-	// Create a form file field
-	part, err := writer.CreateFormFile("file", "speech.wav")
-	if err != nil {
-		return nil, fmt.Errorf("failed to create form file: %w", err)
+	// Create a form file field holding the file content
+	if err := writeAudioPart(writer, speech, format); err != nil {
+		return nil, err
 	}
 
-	// Copy the file content to the form field
-	_, err = io.Copy(part, speech)
+	// Add audio_codec parameter, detected unless provided
+	err = writer.WriteField("audio_codec", string(format.codec))
 	if err != nil {
-		return nil, fmt.Errorf("failed to copy file content: %w", err)
+		return nil, fmt.Errorf("failed to write audio_codec field: %w", err)
 	}
 
 	// Add model parameter if provided
@@ -95,32 +213,56 @@ func (c *Client) buildSpeechToTextRequest(endpoint string, speech io.Reader, par
 		}
 	}
 
+	// Add with_diarization parameter if provided
+	if params.WithDiarization != nil {
+		err = writer.WriteField("with_diarization", fmt.Sprintf("%t", *params.WithDiarization))
+		if err != nil {
+			return nil, fmt.Errorf("failed to write with_diarization field: %w", err)
+		}
+	}
+
+	// Add num_speakers parameter if provided
+	if params.NumSpeakers != nil {
+		err = writer.WriteField("num_speakers", strconv.Itoa(*params.NumSpeakers))
+		if err != nil {
+			return nil, fmt.Errorf("failed to write num_speakers field: %w", err)
+		}
+	}
+
 	// Close the multipart writer
 	err = writer.Close()
 	if err != nil {
 		return nil, fmt.Errorf("failed to close multipart writer: %w", err)
 	}
 
-	return c.makeHTTPRequest(http.MethodPost, c.baseURL+endpoint, &requestBody, writer.FormDataContentType())
+	return c.makeHTTPRequest(ctx, http.MethodPost, c.baseURL+endpoint, requestBody.Bytes(), writer.FormDataContentType())
 }
 
 // buildSpeechToTextTranslateRequest builds a multipart form request for speech-to-text translation.
-func (c *Client) buildSpeechToTextTranslateRequest(endpoint string, speech io.Reader, params SpeechToTextTranslateParams) (*http.Response, error) {
-	var err error
+func (c *Client) buildSpeechToTextTranslateRequest(ctx context.Context, endpoint string, speech io.Reader, params SpeechToTextTranslateParams) (*http.Response, error) {
+	if params.NumSpeakers != nil && *params.NumSpeakers < 1 {
+		return nil, fmt.Errorf("num_speakers must be at least 1, got %d", *params.NumSpeakers)
+	}
+	if err := validateSpeechToTextModel(endpoint, modelID(params.Model), nil); err != nil {
+		return nil, err
+	}
+	format, speech, err := detectAudioFormat(speech, params.AudioCodec)
+	if err != nil {
+		return nil, err
+	}
 
 	// Create a buffer to store the multipart form data
 	var requestBody bytes.Buffer
 	writer := multipart.NewWriter(&requestBody)
-
-	// Create a form file field
-	var part io.Writer
-	part, err = writer.CreateFormFile("file", "speech.wav")
-	if err != nil {
-		return nil, fmt.Errorf("failed to create form file: %w", err)
+	// Create a form file field holding the file content
+	if err := writeAudioPart(writer, speech, format); err != nil {
+		return nil, err
 	}
-	_, err = io.Copy(part, speech)
+
+	// Add audio_codec parameter, detected unless provided
+	err = writer.WriteField("audio_codec", string(format.codec))
 	if err != nil {
-		return nil, fmt.Errorf("failed to copy file content: %w", err)
+		return nil, fmt.Errorf("failed to write audio_codec field: %w", err)
 	}
 
 	// Add prompt parameter if provided
@@ -139,11 +281,19 @@ func (c *Client) buildSpeechToTextTranslateRequest(endpoint string, speech io.Re
 		}
 	}
 
-	// Add audio_codec parameter if provided
-	if params.AudioCodec != nil {
-		err = writer.WriteField("audio_codec", string(*params.AudioCodec))
+	// Add with_diarization parameter if provided
+	if params.WithDiarization != nil {
+		err = writer.WriteField("with_diarization", fmt.Sprintf("%t", *params.WithDiarization))
+		if err != nil {
+			return nil, fmt.Errorf("failed to write with_diarization field: %w", err)
+		}
+	}
+
+	// Add num_speakers parameter if provided
+	if params.NumSpeakers != nil {
+		err = writer.WriteField("num_speakers", strconv.Itoa(*params.NumSpeakers))
 		if err != nil {
-			return nil, fmt.Errorf("failed to write audio_codec field: %w", err)
+			return nil, fmt.Errorf("failed to write num_speakers field: %w", err)
 		}
 	}
 
@@ -153,60 +303,7 @@ func (c *Client) buildSpeechToTextTranslateRequest(endpoint string, speech io.Re
 		return nil, fmt.Errorf("failed to close multipart writer: %w", err)
 	}
 
-	return c.makeHTTPRequest(http.MethodPost, c.baseURL+endpoint, &requestBody, writer.FormDataContentType())
-}
-
-// HTTPError represents an error response from the Sarvam AI API.
-type HTTPError struct {
-	StatusCode int
-	Message    string
-	Code       string
-	RequestID  string
-}
-
-// Error implements the error interface for HTTPError.
-func (e *HTTPError) Error() string {
-	if e.Code != "" && e.RequestID != "" {
-		return fmt.Sprintf("status code: %d, code: %s, message: %s, request_id: %s", e.StatusCode, e.Code, e.Message, e.RequestID)
-	}
-	return fmt.Sprintf("status code: %d, message: %s", e.StatusCode, e.Message)
-}
-
-// parseAPIError parses an HTTP error response from the Sarvam AI API.
-func parseAPIError(resp *http.Response) error {
-	// Try to read the response body
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		// If we can't read the body, return a basic error
-		return &HTTPError{
-			StatusCode: resp.StatusCode,
-			Message:    resp.Status,
-		}
-	}
-
-	// Try to parse as API error format
-	var apiError struct {
-		Error struct {
-			Message   string `json:"message"`
-			Code      string `json:"code"`
-			RequestID string `json:"request_id"`
-		} `json:"error"`
-	}
-
-	if err := json.Unmarshal(body, &apiError); err == nil && apiError.Error.Message != "" {
-		return &HTTPError{
-			StatusCode: resp.StatusCode,
-			Message:    apiError.Error.Message,
-			Code:       apiError.Error.Code,
-			RequestID:  apiError.Error.RequestID,
-		}
-	}
-
-	// If parsing fails, return the raw body as message
-	return &HTTPError{
-		StatusCode: resp.StatusCode,
-		Message:    string(body),
-	}
+	return c.makeHTTPRequest(ctx, http.MethodPost, c.baseURL+endpoint, requestBody.Bytes(), writer.FormDataContentType())
 }
 
 func Ptr[T any](v T) *T {
@@ -224,8 +321,8 @@ func init() {
 }
 
 // SetAPIKey sets the API key for the default client and creates a new client instance
-func SetAPIKey(apiKey string) {
-	defaultClient = NewClient(apiKey)
+func SetAPIKey(apiKey string, opts ...Option) {
+	defaultClient = NewClient(apiKey, opts...)
 }
 
 // GetDefaultClient returns the default client instance
@@ -1,6 +1,8 @@
 package sarvam
 
 import (
+	"context"
+	"errors"
 	"io"
 	"net/http"
 	"net/http/httptest"
@@ -29,11 +31,43 @@ func TestMakeHTTPRequest(t *testing.T) {
 	client := NewClient(testApiKey)
 	client.SetBaseURL(httpTestServer.URL)
 
-	response, err := client.makeJsonHTTPRequest("GET", httpTestServer.URL+"/v1/test", nil)
+	response, err := client.makeJsonHTTPRequest(context.Background(), "GET", httpTestServer.URL+"/v1/test", nil)
 	assert.NoError(t, err)
 	assert.Equal(t, response.StatusCode, 200)
 }
 
+func TestMakeHTTPRequestCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	client := NewClient("test")
+	_, err := client.makeJsonHTTPRequest(ctx, "GET", "http://127.0.0.1/v1/test", nil)
+	assert.ErrorIs(t, err, ErrRequestCanceled)
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+func TestSpeechToTextWithContextDeadline(t *testing.T) {
+	done := make(chan struct{})
+	httpTestServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-done:
+		}
+	}))
+	defer httpTestServer.Close()
+	defer close(done)
+
+	client := NewClient("test")
+	client.SetBaseURL(httpTestServer.URL)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	_, err := client.SpeechToTextWithContext(ctx, strings.NewReader("RIFF\x00\x00\x00\x00WAVE"), SpeechToTextParams{})
+	assert.ErrorIs(t, err, ErrRequestCanceled)
+	assert.True(t, errors.Is(err, context.DeadlineExceeded))
+}
+
 type handler struct {
 	apiKey string
 }
@@ -0,0 +1,207 @@
+package sarvam
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// ErrConversationTooLong is returned when the latest turn of a conversation does not fit
+// within its token budget even after all earlier turns have been trimmed.
+var ErrConversationTooLong = errors.New("conversation does not fit within its token budget")
+
+// Summarizer condenses turns trimmed from a conversation. It receives the previous summary,
+// which may be empty, and the trimmed messages, and returns the new summary.
+type Summarizer func(ctx context.Context, summary string, trimmed []Message) (string, error)
+
+// Conversation holds the system prompt and history of a multi-turn chat and sends new
+// turns with the history attached. Replies are appended to the history automatically.
+//
+// When TokenBudget is set, the oldest turns are trimmed before sending so that the prompt
+// stays within it. If Summarize is set, trimmed turns are condensed into Summary, which is
+// sent along with the system prompt.
+//
+// A Conversation can be marshaled to JSON for persistence and restored with LoadConversation.
+type Conversation struct {
+	Model        ChatCompletionModel   `json:"model"`
+	SystemPrompt string                `json:"system_prompt,omitempty"`
+	Summary      string                `json:"summary,omitempty"`
+	Messages     []Message             `json:"messages"`
+	TokenBudget  int                   `json:"token_budget,omitempty"` // Maximum estimated prompt tokens; zero means unlimited
+	Params       *ChatCompletionParams `json:"params,omitempty"`
+
+	// Summarize condenses trimmed turns. If nil, trimmed turns are dropped.
+	Summarize Summarizer `json:"-"`
+	// CountTokens estimates the number of tokens in a prompt. If nil, EstimateTokens is used.
+	CountTokens func(messages []Message) int `json:"-"`
+
+	client *Client
+}
+
+// NewConversation creates an empty conversation that sends its turns through client.
+func NewConversation(client *Client, model ChatCompletionModel, systemPrompt string) *Conversation {
+	return &Conversation{
+		Model:        model,
+		SystemPrompt: systemPrompt,
+		Messages:     []Message{},
+		client:       client,
+	}
+}
+
+// LoadConversation restores a conversation marshaled to JSON and attaches client to it.
+// Summarize and CountTokens are not persisted and must be set again if needed.
+func LoadConversation(data []byte, client *Client) (*Conversation, error) {
+	var conversation Conversation
+	if err := json.Unmarshal(data, &conversation); err != nil {
+		return nil, fmt.Errorf("failed to decode conversation: %w", err)
+	}
+	conversation.client = client
+	return &conversation, nil
+}
+
+// Send adds a user message to the conversation and returns the model's reply, which is
+// appended to the history as well. The history is left unchanged if the request fails.
+func (c *Conversation) Send(content string) (*ChatCompletionResponse, error) {
+	return c.SendWithContext(context.Background(), content)
+}
+
+// SendWithContext is like Send but uses ctx to control cancellation and deadlines.
+func (c *Conversation) SendWithContext(ctx context.Context, content string) (*ChatCompletionResponse, error) {
+	if c.client == nil {
+		return nil, fmt.Errorf("conversation has no client, create it with NewConversation or LoadConversation")
+	}
+
+	messages := append(append([]Message(nil), c.Messages...), NewUserMessage(content))
+	messages, summary, err := c.fit(ctx, messages, c.Summary)
+	if err != nil {
+		return nil, err
+	}
+
+	response, err := c.client.ChatCompletionWithContext(ctx, c.prompt(summary, messages), c.Model, c.Params)
+	if err != nil {
+		return nil, err
+	}
+	if len(response.Choices) > 0 {
+		messages = append(messages, response.Choices[0].Message)
+	}
+
+	c.Messages = messages
+	c.Summary = summary
+	return response, nil
+}
+
+// Append adds messages to the history without sending them.
+func (c *Conversation) Append(messages ...Message) {
+	c.Messages = append(c.Messages, messages...)
+}
+
+// Prompt returns the messages that would be sent for the current history: the system
+// prompt, the summary of trimmed turns and the remaining turns.
+func (c *Conversation) Prompt() []Message {
+	return c.prompt(c.Summary, c.Messages)
+}
+
+func (c *Conversation) prompt(summary string, messages []Message) []Message {
+	var system []string
+	if c.SystemPrompt != "" {
+		system = append(system, c.SystemPrompt)
+	}
+	if summary != "" {
+		system = append(system, "Summary of the earlier conversation:\n"+summary)
+	}
+
+	prompt := make([]Message, 0, len(messages)+1)
+	if len(system) > 0 {
+		prompt = append(prompt, NewSystemMessage(strings.Join(system, "\n\n")))
+	}
+	return append(prompt, messages...)
+}
+
+// fit trims the oldest turns from messages until the prompt fits within the token budget,
+// summarizing them if a summarizer is set.
+func (c *Conversation) fit(ctx context.Context, messages []Message, summary string) ([]Message, string, error) {
+	if c.TokenBudget <= 0 {
+		return messages, summary, nil
+	}
+	countTokens := c.CountTokens
+	if countTokens == nil {
+		countTokens = EstimateTokens
+	}
+
+	for countTokens(c.prompt(summary, messages)) > c.TokenBudget {
+		var trimmed []Message
+		for countTokens(c.prompt(summary, messages)) > c.TokenBudget {
+			n := oldestTurnLength(messages)
+			if n == 0 {
+				return nil, "", ErrConversationTooLong
+			}
+			trimmed = append(trimmed, messages[:n]...)
+			messages = messages[n:]
+		}
+		if c.Summarize == nil {
+			break
+		}
+
+		var err error
+		summary, err = c.Summarize(ctx, summary, trimmed)
+		if err != nil {
+			return nil, "", fmt.Errorf("failed to summarize conversation: %w", err)
+		}
+	}
+	return messages, summary, nil
+}
+
+// oldestTurnLength returns the number of messages in the oldest turn, which runs up to the
+// next user message. It returns 0 if messages hold a single turn.
+func oldestTurnLength(messages []Message) int {
+	for i := 1; i < len(messages); i++ {
+		if messages[i].Role == string(MessageRoleUser) {
+			return i
+		}
+	}
+	return 0
+}
+
+// EstimateTokens approximates the number of tokens in messages without calling the API.
+// It assumes about three characters per token, which overestimates for English text and
+// is closer for Indic scripts, plus a small overhead per message.
+func EstimateTokens(messages []Message) int {
+	const perMessage = 4
+	tokens := 0
+	for _, message := range messages {
+		characters := utf8.RuneCountInString(message.Content)
+		for _, call := range message.ToolCalls {
+			characters += utf8.RuneCountInString(call.Function.Name) + utf8.RuneCountInString(call.Function.Arguments)
+		}
+		tokens += perMessage + (characters+2)/3
+	}
+	return tokens
+}
+
+// NewModelSummarizer returns a Summarizer that asks model to condense trimmed turns.
+func NewModelSummarizer(client *Client, model ChatCompletionModel) Summarizer {
+	return func(ctx context.Context, summary string, trimmed []Message) (string, error) {
+		var transcript strings.Builder
+		if summary != "" {
+			fmt.Fprintf(&transcript, "Earlier summary:\n%s\n\n", summary)
+		}
+		transcript.WriteString("Conversation:\n")
+		for _, message := range trimmed {
+			if message.Content != "" {
+				fmt.Fprintf(&transcript, "%s: %s\n", message.Role, message.Content)
+			}
+		}
+
+		response, err := client.ChatCompletionWithContext(ctx, []Message{
+			NewSystemMessage("Summarize the conversation below in a few sentences. Keep the facts, names, preferences and decisions needed to continue it. Reply with the summary only."),
+			NewUserMessage(transcript.String()),
+		}, model, nil)
+		if err != nil {
+			return "", err
+		}
+		return strings.TrimSpace(response.GetFirstChoiceContent()), nil
+	}
+}
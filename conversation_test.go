@@ -0,0 +1,157 @@
+package sarvam
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newEchoChatServer returns a server that replies to every chat completion with the
+// number of messages it received, recording the requests.
+func newEchoChatServer(t *testing.T, requests *[]chatCompletionRequest) *httptest.Server {
+	t.Helper()
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req chatCompletionRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		*requests = append(*requests, req)
+		last := req.Messages[len(req.Messages)-1].Content
+		json.NewEncoder(w).Encode(map[string]any{
+			"choices": []map[string]any{{"message": map[string]any{"role": "assistant", "content": "<think>hmm</think>re: " + last}}},
+		})
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+func TestConversationSend(t *testing.T) {
+	var requests []chatCompletionRequest
+	httpTestServer := newEchoChatServer(t, &requests)
+
+	client := NewClient("test", WithBaseURL(httpTestServer.URL))
+	conversation := NewConversation(client, ChatCompletionModelSarvamM, "Be brief.")
+
+	response, err := conversation.Send("one")
+	require.NoError(t, err)
+	assert.Equal(t, "re: one", response.GetFirstChoiceContent())
+
+	_, err = conversation.Send("two")
+	require.NoError(t, err)
+
+	require.Len(t, requests, 2)
+	assert.Equal(t, []Message{
+		NewSystemMessage("Be brief."),
+		NewUserMessage("one"),
+		NewAssistantMessage("re: one"),
+		NewUserMessage("two"),
+	}, requests[1].Messages)
+	assert.Len(t, conversation.Messages, 4)
+}
+
+func TestConversationKeepsHistoryOnError(t *testing.T) {
+	httpTestServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer httpTestServer.Close()
+
+	conversation := NewConversation(NewClient("test", WithBaseURL(httpTestServer.URL)), ChatCompletionModelSarvamM, "")
+	conversation.Append(NewUserMessage("hi"), NewAssistantMessage("hello"))
+
+	_, err := conversation.Send("again")
+	assert.Error(t, err)
+	assert.Len(t, conversation.Messages, 2)
+}
+
+func TestConversationTrimsToBudget(t *testing.T) {
+	var requests []chatCompletionRequest
+	httpTestServer := newEchoChatServer(t, &requests)
+
+	conversation := NewConversation(NewClient("test", WithBaseURL(httpTestServer.URL)), ChatCompletionModelSarvamM, "system")
+	conversation.TokenBudget = 3
+	conversation.CountTokens = func(messages []Message) int { return len(messages) }
+
+	for _, content := range []string{"one", "two", "three"} {
+		_, err := conversation.Send(content)
+		require.NoError(t, err)
+	}
+
+	// A full earlier turn no longer fits next to the system prompt and the new message.
+	assert.Equal(t, []Message{
+		NewSystemMessage("system"),
+		NewUserMessage("three"),
+	}, requests[2].Messages)
+}
+
+func TestConversationSummarizesTrimmedTurns(t *testing.T) {
+	var requests []chatCompletionRequest
+	httpTestServer := newEchoChatServer(t, &requests)
+
+	var summarized [][]Message
+	conversation := NewConversation(NewClient("test", WithBaseURL(httpTestServer.URL)), ChatCompletionModelSarvamM, "system")
+	conversation.TokenBudget = 4
+	conversation.CountTokens = func(messages []Message) int { return len(messages) }
+	conversation.Summarize = func(ctx context.Context, summary string, trimmed []Message) (string, error) {
+		summarized = append(summarized, trimmed)
+		return strings.TrimSpace(summary + " " + trimmed[0].Content), nil
+	}
+
+	for _, content := range []string{"one", "two", "three"} {
+		_, err := conversation.Send(content)
+		require.NoError(t, err)
+	}
+
+	require.Len(t, summarized, 1)
+	assert.Equal(t, []Message{NewUserMessage("one"), NewAssistantMessage("re: one")}, summarized[0])
+	assert.Equal(t, "one", conversation.Summary)
+	assert.Equal(t, "system\n\nSummary of the earlier conversation:\none", requests[2].Messages[0].Content)
+}
+
+func TestConversationTooLong(t *testing.T) {
+	conversation := NewConversation(NewClient("test"), ChatCompletionModelSarvamM, "")
+	conversation.TokenBudget = 5
+
+	_, err := conversation.Send(strings.Repeat("long ", 100))
+	assert.ErrorIs(t, err, ErrConversationTooLong)
+	assert.Empty(t, conversation.Messages)
+}
+
+func TestConversationJSON(t *testing.T) {
+	conversation := NewConversation(NewClient("test"), ChatCompletionModelSarvamM, "Be brief.")
+	conversation.TokenBudget = 1000
+	conversation.Params = &ChatCompletionParams{Temperature: Ptr(0.2), ToolChoice: Ptr(ToolChoiceAuto)}
+	conversation.Append(NewUserMessage("hi"), NewAssistantMessage("hello"))
+
+	data, err := json.Marshal(conversation)
+	require.NoError(t, err)
+
+	client := NewClient("other")
+	restored, err := LoadConversation(data, client)
+	require.NoError(t, err)
+	assert.Same(t, client, restored.client)
+	assert.Equal(t, conversation.Prompt(), restored.Prompt())
+	assert.Equal(t, 1000, restored.TokenBudget)
+	assert.Equal(t, ToolChoiceAuto, *restored.Params.ToolChoice)
+}
+
+func TestEstimateTokens(t *testing.T) {
+	assert.Equal(t, 4+2, EstimateTokens([]Message{NewUserMessage("hello")}))
+	// Devanagari text is counted in characters, not bytes.
+	assert.Equal(t, 4+2, EstimateTokens([]Message{NewUserMessage("नमस्ते")}))
+}
+
+func TestNewModelSummarizer(t *testing.T) {
+	var requests []chatCompletionRequest
+	httpTestServer := newEchoChatServer(t, &requests)
+
+	summarize := NewModelSummarizer(NewClient("test", WithBaseURL(httpTestServer.URL)), ChatCompletionModelSarvamM)
+	summary, err := summarize(context.Background(), "Earlier.", []Message{NewUserMessage("My name is Asha"), NewAssistantMessage("Hi Asha")})
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(summary, "re: "))
+	assert.Contains(t, requests[0].Messages[1].Content, "user: My name is Asha")
+	assert.Contains(t, requests[0].Messages[1].Content, "Earlier summary:\nEarlier.")
+}
@@ -0,0 +1,220 @@
+package sarvam
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Sentinel errors matched by *HTTPError with errors.Is, classifying API failures.
+var (
+	ErrUnauthorized  = errors.New("unauthorized")    // The API key is missing, invalid or lacks access (401, 403)
+	ErrNotFound      = errors.New("not found")       // The resource or endpoint does not exist (404)
+	ErrValidation    = errors.New("invalid request") // The request was rejected as invalid (400, 422); see HTTPError.Fields
+	ErrRateLimited   = errors.New("rate limited")    // Too many requests (429); see HTTPError.RetryAfter
+	ErrQuotaExceeded = errors.New("quota exceeded")  // The account has run out of credits
+	ErrServer        = errors.New("server error")    // The API failed to handle the request (5xx)
+	ErrTimeout       = errors.New("server timeout")  // The API timed out (408, 504)
+)
+
+// Error codes returned by the API in the error body.
+const (
+	errorCodeInvalidRequest    = "invalid_request_error"
+	errorCodeInvalidAPIKey     = "invalid_api_key_error"
+	errorCodeInsufficientQuota = "insufficient_quota_error"
+	errorCodeRateLimitExceeded = "rate_limit_exceeded_error"
+	errorCodeUnprocessable     = "unprocessable_entity_error"
+	errorCodeInternalServer    = "internal_server_error"
+)
+
+// HTTPError represents an error response from the Sarvam AI API.
+//
+// Use errors.Is with the sentinel errors, such as ErrRateLimited or ErrValidation, to tell
+// failures apart, and errors.As to access the details.
+type HTTPError struct {
+	StatusCode int
+	Message    string
+	Code       string
+	RequestID  string
+	RetryAfter time.Duration // Delay requested by the Retry-After header, if any
+	Fields     []FieldError  // Invalid fields of a rejected request, if reported
+}
+
+// FieldError describes why a single field of a request is invalid.
+type FieldError struct {
+	Field   string // Dotted path of the field, such as "input" or "messages.0.content"
+	Message string
+}
+
+func (e FieldError) String() string {
+	if e.Field == "" {
+		return e.Message
+	}
+	return e.Field + ": " + e.Message
+}
+
+// Error implements the error interface for HTTPError.
+func (e *HTTPError) Error() string {
+	if e.Code != "" && e.RequestID != "" {
+		return fmt.Sprintf("status code: %d, code: %s, message: %s, request_id: %s", e.StatusCode, e.Code, e.Message, e.RequestID)
+	}
+	return fmt.Sprintf("status code: %d, message: %s", e.StatusCode, e.Message)
+}
+
+// Is reports whether the error belongs to the class of failures target stands for.
+func (e *HTTPError) Is(target error) bool {
+	switch target {
+	case ErrUnauthorized:
+		return e.StatusCode == http.StatusUnauthorized || e.StatusCode == http.StatusForbidden || e.Code == errorCodeInvalidAPIKey
+	case ErrNotFound:
+		return e.StatusCode == http.StatusNotFound
+	case ErrValidation:
+		return e.StatusCode == http.StatusBadRequest || e.StatusCode == http.StatusUnprocessableEntity ||
+			e.Code == errorCodeInvalidRequest || e.Code == errorCodeUnprocessable
+	case ErrRateLimited:
+		return (e.StatusCode == http.StatusTooManyRequests || e.Code == errorCodeRateLimitExceeded) && !e.quotaExceeded()
+	case ErrQuotaExceeded:
+		return e.quotaExceeded()
+	case ErrServer:
+		return e.StatusCode >= 500 || e.Code == errorCodeInternalServer
+	case ErrTimeout:
+		return e.StatusCode == http.StatusRequestTimeout || e.StatusCode == http.StatusGatewayTimeout
+	}
+	return false
+}
+
+// quotaExceeded reports whether the error is due to the account running out of credits,
+// which the API reports with a 429 or 402 status.
+func (e *HTTPError) quotaExceeded() bool {
+	return e.Code == errorCodeInsufficientQuota || e.StatusCode == http.StatusPaymentRequired
+}
+
+// Retryable reports whether sending the same request again may succeed: the API was rate
+// limited, timed out or failed on its side. Exhausted quota is not retryable.
+func (e *HTTPError) Retryable() bool {
+	return errors.Is(e, ErrRateLimited) || errors.Is(e, ErrTimeout) || errors.Is(e, ErrServer)
+}
+
+// Temporary reports whether the error is temporary. It is the same as Retryable.
+func (e *HTTPError) Temporary() bool {
+	return e.Retryable()
+}
+
+// IsRetryable reports whether err, or an error it wraps, is an API error that may succeed
+// if the request is sent again, or a client-side throttling error.
+func IsRetryable(err error) bool {
+	var retryable interface{ Retryable() bool }
+	if errors.As(err, &retryable) {
+		return retryable.Retryable()
+	}
+	return errors.Is(err, ErrThrottled)
+}
+
+// parseAPIError parses an HTTP error response from the Sarvam AI API.
+func parseAPIError(resp *http.Response) error {
+	httpErr := &HTTPError{StatusCode: resp.StatusCode}
+	if delay, ok := parseRetryAfter(resp.Header.Get("Retry-After"), time.Now()); ok {
+		httpErr.RetryAfter = delay
+	}
+
+	// Try to read the response body
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		// If we can't read the body, return a basic error
+		httpErr.Message = resp.Status
+		return httpErr
+	}
+
+	// Try to parse as API error format
+	var apiError struct {
+		Error struct {
+			Message   string `json:"message"`
+			Code      string `json:"code"`
+			RequestID string `json:"request_id"`
+		} `json:"error"`
+		// Validation failures may list the invalid fields instead.
+		Detail json.RawMessage `json:"detail"`
+	}
+	if err := json.Unmarshal(body, &apiError); err == nil {
+		httpErr.Message = apiError.Error.Message
+		httpErr.Code = apiError.Error.Code
+		httpErr.RequestID = apiError.Error.RequestID
+		httpErr.Fields = parseFieldErrors(apiError.Detail)
+		if httpErr.Message == "" && len(httpErr.Fields) > 0 {
+			messages := make([]string, len(httpErr.Fields))
+			for i, field := range httpErr.Fields {
+				messages[i] = field.String()
+			}
+			httpErr.Message = strings.Join(messages, "; ")
+		}
+		if httpErr.Message != "" {
+			return httpErr
+		}
+	}
+
+	// If parsing fails, return the raw body as message
+	httpErr.Message = string(body)
+	return httpErr
+}
+
+// parseFieldErrors parses the detail of a validation failure, given either as a list of
+// {"loc": [...], "msg": "..."} objects or as a single message.
+func parseFieldErrors(detail json.RawMessage) []FieldError {
+	if len(detail) == 0 {
+		return nil
+	}
+
+	var message string
+	if err := json.Unmarshal(detail, &message); err == nil {
+		return []FieldError{{Message: message}}
+	}
+
+	var items []struct {
+		Loc []any  `json:"loc"`
+		Msg string `json:"msg"`
+	}
+	if err := json.Unmarshal(detail, &items); err != nil {
+		return nil
+	}
+	fields := make([]FieldError, 0, len(items))
+	for _, item := range items {
+		var path []string
+		for i, part := range item.Loc {
+			// The location starts with where the field was found, such as the body.
+			if i == 0 && (part == "body" || part == "query" || part == "path") {
+				continue
+			}
+			path = append(path, fmt.Sprint(part))
+		}
+		fields = append(fields, FieldError{Field: strings.Join(path, "."), Message: item.Msg})
+	}
+	return fields
+}
+
+// quotaExceededResponse reports whether resp is an error response due to exhausted quota.
+// The body is read and replaced so that it can still be parsed afterwards.
+func quotaExceededResponse(resp *http.Response) bool {
+	if resp.Body == nil {
+		return false
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 64<<10))
+	resp.Body = struct {
+		io.Reader
+		io.Closer
+	}{io.MultiReader(bytes.NewReader(body), resp.Body), resp.Body}
+	if err != nil {
+		return false
+	}
+
+	var apiError struct {
+		Error struct {
+			Code string `json:"code"`
+		} `json:"error"`
+	}
+	return json.Unmarshal(body, &apiError) == nil && apiError.Error.Code == errorCodeInsufficientQuota
+}
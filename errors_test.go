@@ -0,0 +1,96 @@
+package sarvam
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHTTPErrorClassification(t *testing.T) {
+	tests := []struct {
+		err       *HTTPError
+		is        error
+		retryable bool
+	}{
+		{&HTTPError{StatusCode: 401}, ErrUnauthorized, false},
+		{&HTTPError{StatusCode: 403, Code: "invalid_api_key_error"}, ErrUnauthorized, false},
+		{&HTTPError{StatusCode: 404}, ErrNotFound, false},
+		{&HTTPError{StatusCode: 400, Code: "invalid_request_error"}, ErrValidation, false},
+		{&HTTPError{StatusCode: 422}, ErrValidation, false},
+		{&HTTPError{StatusCode: 429, Code: "rate_limit_exceeded_error"}, ErrRateLimited, true},
+		{&HTTPError{StatusCode: 429, Code: "insufficient_quota_error"}, ErrQuotaExceeded, false},
+		{&HTTPError{StatusCode: 500, Code: "internal_server_error"}, ErrServer, true},
+		{&HTTPError{StatusCode: 503}, ErrServer, true},
+		{&HTTPError{StatusCode: 408}, ErrTimeout, true},
+	}
+	sentinels := []error{ErrUnauthorized, ErrNotFound, ErrValidation, ErrRateLimited, ErrQuotaExceeded, ErrServer, ErrTimeout}
+	for _, tt := range tests {
+		t.Run(tt.err.Error(), func(t *testing.T) {
+			wrapped := fmt.Errorf("failed to translate: %w", tt.err)
+			assert.ErrorIs(t, wrapped, tt.is)
+			for _, sentinel := range sentinels {
+				if sentinel != tt.is {
+					assert.NotErrorIs(t, wrapped, sentinel)
+				}
+			}
+			assert.Equal(t, tt.retryable, tt.err.Retryable())
+			assert.Equal(t, tt.retryable, tt.err.Temporary())
+			assert.Equal(t, tt.retryable, IsRetryable(wrapped))
+		})
+	}
+
+	assert.True(t, IsRetryable(ErrThrottled))
+	assert.False(t, IsRetryable(errors.New("other")))
+}
+
+func TestParseAPIErrorDetails(t *testing.T) {
+	httpTestServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case EndpointTranslate:
+			w.WriteHeader(http.StatusUnprocessableEntity)
+			w.Write([]byte(`{"detail":[{"loc":["body","input"],"msg":"field required","type":"missing"},{"loc":["body","speaker",0],"msg":"invalid"}]}`))
+		case EndpointTextLID:
+			w.Header().Set("Retry-After", "7")
+			w.WriteHeader(http.StatusTooManyRequests)
+			w.Write([]byte(`{"error":{"message":"slow down","code":"rate_limit_exceeded_error"}}`))
+		}
+	}))
+	defer httpTestServer.Close()
+	client := NewClient("test", WithBaseURL(httpTestServer.URL))
+
+	_, err := client.Translate("hello", LanguageEnglish, LanguageHindi, nil)
+	require.ErrorIs(t, err, ErrValidation)
+	var httpErr *HTTPError
+	require.ErrorAs(t, err, &httpErr)
+	assert.Equal(t, []FieldError{{Field: "input", Message: "field required"}, {Field: "speaker.0", Message: "invalid"}}, httpErr.Fields)
+	assert.Equal(t, "input: field required; speaker.0: invalid", httpErr.Message)
+
+	_, err = client.IdentifyLanguage("hello")
+	require.ErrorIs(t, err, ErrRateLimited)
+	require.ErrorAs(t, err, &httpErr)
+	assert.Equal(t, 7*time.Second, httpErr.RetryAfter)
+}
+
+func TestRetrySkipsExhaustedQuota(t *testing.T) {
+	var attempts atomic.Int32
+	httpTestServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts.Add(1)
+		w.WriteHeader(http.StatusTooManyRequests)
+		w.Write([]byte(`{"error":{"message":"out of credits","code":"insufficient_quota_error"}}`))
+	}))
+	defer httpTestServer.Close()
+
+	client := NewClient("test", WithBaseURL(httpTestServer.URL), WithRetryPolicy(RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Millisecond}))
+	_, err := client.IdentifyLanguage("hello")
+	assert.ErrorIs(t, err, ErrQuotaExceeded)
+	assert.True(t, strings.Contains(err.Error(), "out of credits"))
+	assert.Equal(t, int32(1), attempts.Load())
+}
@@ -0,0 +1,32 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"os"
+
+	"code.abhai.dev/sarvam"
+)
+
+func main() {
+	client := sarvam.NewClient(os.Getenv("SARVAM_API_KEY"))
+
+	stream, err := client.ChatCompletionStream([]sarvam.Message{
+		sarvam.NewSystemMessage("You are a helpful assistant that answers in short"),
+		sarvam.NewUserMessage("Tell me about the Western Ghats."),
+	}, sarvam.ChatCompletionModelSarvamM, nil)
+	if err != nil {
+		log.Fatalf("Error: %v", err)
+	}
+	defer stream.Close()
+
+	for chunk, err := range stream.All() {
+		if err != nil {
+			log.Fatalf("Error: %v", err)
+		}
+		for _, choice := range chunk.Choices {
+			fmt.Print(choice.Delta.Content)
+		}
+	}
+	fmt.Println()
+}
@@ -0,0 +1,60 @@
+package sarvam
+
+import (
+	"context"
+	"io"
+)
+
+// The interfaces below each cover one API surface of Client, so that code can depend on
+// only what it uses and be tested with a fake, such as those in the sarvamtest package.
+
+// Translator translates text between languages.
+type Translator interface {
+	Translate(input string, sourceLanguageCode, targetLanguageCode Language, params *TranslateParams) (*TranslationResponse, error)
+	TranslateWithContext(ctx context.Context, input string, sourceLanguageCode, targetLanguageCode Language, params *TranslateParams) (*TranslationResponse, error)
+}
+
+// Transliterator converts text between scripts.
+type Transliterator interface {
+	Transliterate(input string, sourceLanguage Language, targetLanguage Language, params *TransliterateParams) (*TransliterationResponse, error)
+	TransliterateWithContext(ctx context.Context, input string, sourceLanguage Language, targetLanguage Language, params *TransliterateParams) (*TransliterationResponse, error)
+}
+
+// LanguageIdentifier identifies the language and script of text.
+type LanguageIdentifier interface {
+	IdentifyLanguage(input string) (*LanguageIdentificationResponse, error)
+	IdentifyLanguageWithContext(ctx context.Context, input string) (*LanguageIdentificationResponse, error)
+}
+
+// Synthesizer converts text to speech.
+type Synthesizer interface {
+	TextToSpeech(text string, targetLanguage Language, params TextToSpeechParams) (*TextToSpeechResponse, error)
+	TextToSpeechWithContext(ctx context.Context, text string, targetLanguage Language, params TextToSpeechParams) (*TextToSpeechResponse, error)
+	TextToSpeechStream(text string, targetLanguage Language, params TextToSpeechParams) (io.ReadCloser, error)
+	TextToSpeechStreamWithContext(ctx context.Context, text string, targetLanguage Language, params TextToSpeechParams) (io.ReadCloser, error)
+}
+
+// Transcriber converts speech to text, optionally translating it to English.
+type Transcriber interface {
+	SpeechToText(speech io.Reader, params SpeechToTextParams) (*SpeechToTextResponse, error)
+	SpeechToTextWithContext(ctx context.Context, speech io.Reader, params SpeechToTextParams) (*SpeechToTextResponse, error)
+	SpeechToTextTranslate(speech io.Reader, params SpeechToTextTranslateParams) (*SpeechToTextTranslateResponse, error)
+	SpeechToTextTranslateWithContext(ctx context.Context, speech io.Reader, params SpeechToTextTranslateParams) (*SpeechToTextTranslateResponse, error)
+}
+
+// ChatCompleter creates chat completions, whole or streamed.
+type ChatCompleter interface {
+	ChatCompletion(messages []Message, model ChatCompletionModel, req *ChatCompletionParams) (*ChatCompletionResponse, error)
+	ChatCompletionWithContext(ctx context.Context, messages []Message, model ChatCompletionModel, req *ChatCompletionParams) (*ChatCompletionResponse, error)
+	ChatCompletionStream(messages []Message, model ChatCompletionModel, req *ChatCompletionParams) (*ChatCompletionStream, error)
+	ChatCompletionStreamWithContext(ctx context.Context, messages []Message, model ChatCompletionModel, req *ChatCompletionParams) (*ChatCompletionStream, error)
+}
+
+var (
+	_ Translator         = (*Client)(nil)
+	_ Transliterator     = (*Client)(nil)
+	_ LanguageIdentifier = (*Client)(nil)
+	_ Synthesizer        = (*Client)(nil)
+	_ Transcriber        = (*Client)(nil)
+	_ ChatCompleter      = (*Client)(nil)
+)
@@ -0,0 +1,522 @@
+// Package websocket implements the subset of the WebSocket protocol (RFC 6455) needed by
+// the streaming endpoints of the Sarvam AI API: a client, a server-side upgrade for tests,
+// and text and binary messages with control frames handled transparently. Extensions and
+// subprotocols are not supported.
+package websocket
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/tls"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// MessageType is the type of a data message.
+type MessageType int
+
+const (
+	TextMessage   MessageType = 1
+	BinaryMessage MessageType = 2
+)
+
+const (
+	opContinuation = 0x0
+	opClose        = 0x8
+	opPing         = 0x9
+	opPong         = 0xA
+)
+
+// Close codes defined by RFC 6455.
+const (
+	CloseNormal        = 1000
+	CloseGoingAway     = 1001
+	CloseProtocolError = 1002
+	CloseNoStatus      = 1005
+	CloseAbnormal      = 1006
+)
+
+// MaxMessageSize is the largest message ReadMessage accepts.
+const MaxMessageSize = 16 << 20
+
+// acceptGUID is appended to the handshake key to compute the accept header.
+const acceptGUID = "258EAFA5-E914-47DA-95CA-C5AB0DC85B11"
+
+// ErrBadHandshake is returned by Dial when the server does not upgrade the connection.
+// The server's response is returned alongside it.
+var ErrBadHandshake = errors.New("websocket: bad handshake")
+
+// CloseError is returned by ReadMessage when the peer closes the connection.
+type CloseError struct {
+	Code int
+	Text string
+}
+
+func (e *CloseError) Error() string {
+	return fmt.Sprintf("websocket: closed with code %d %s", e.Code, e.Text)
+}
+
+// Conn is a WebSocket connection. ReadMessage must not be called concurrently, but
+// WriteMessage and Close may be called from any goroutine.
+type Conn struct {
+	conn   net.Conn
+	reader *bufio.Reader
+	client bool // Clients mask the frames they send
+
+	writeMu sync.Mutex
+	closed  bool // Whether a close frame has been sent
+}
+
+// Dialer contains options for connecting to a WebSocket server. The zero value connects
+// directly, with the default TLS settings.
+type Dialer struct {
+	// NetDialContext opens the TCP connection to the server or proxy. If nil, a net.Dialer
+	// is used.
+	NetDialContext func(ctx context.Context, network, addr string) (net.Conn, error)
+	// TLSClientConfig configures wss:// connections and connections to https:// proxies.
+	// Its ServerName defaults to the host being connected to.
+	TLSClientConfig *tls.Config
+	// Proxy returns the URL of an HTTP proxy to tunnel the connection through with CONNECT,
+	// or nil for none, like http.Transport.Proxy. It is called with a request for the
+	// http:// or https:// equivalent of the WebSocket URL.
+	Proxy func(*http.Request) (*url.URL, error)
+}
+
+// Dial opens a WebSocket connection to a ws:// or wss:// URL with the zero Dialer.
+func Dial(ctx context.Context, rawURL string, header http.Header) (*Conn, *http.Response, error) {
+	var d Dialer
+	return d.Dial(ctx, rawURL, header)
+}
+
+// Dial opens a WebSocket connection to a ws:// or wss:// URL, sending header with the
+// handshake request. If the server responds without upgrading, the response is returned
+// with its body buffered, along with ErrBadHandshake.
+func (d *Dialer) Dial(ctx context.Context, rawURL string, header http.Header) (*Conn, *http.Response, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, nil, err
+	}
+	var port string
+	switch u.Scheme {
+	case "ws":
+		port = "80"
+	case "wss":
+		port = "443"
+	default:
+		return nil, nil, fmt.Errorf("websocket: unsupported scheme %q", u.Scheme)
+	}
+	addr := u.Host
+	if u.Port() == "" {
+		addr = net.JoinHostPort(u.Hostname(), port)
+	}
+
+	conn, err := d.dial(ctx, u, addr)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Abort the handshake if ctx is done before it completes. Once stopped, ctx may be
+	// canceled without affecting the connection.
+	stop := context.AfterFunc(ctx, func() { conn.Close() })
+	ws, resp, err := d.handshake(ctx, conn, u, header)
+	if !stop() && err == nil {
+		err = ctx.Err()
+	}
+	if err != nil {
+		conn.Close()
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			err = ctxErr
+		}
+		return nil, resp, err
+	}
+	return ws, resp, nil
+}
+
+// dial opens the TCP connection for u, whose server listens on addr, tunneling it through
+// the proxy if there is one.
+func (d *Dialer) dial(ctx context.Context, u *url.URL, addr string) (net.Conn, error) {
+	netDial := d.NetDialContext
+	if netDial == nil {
+		var dialer net.Dialer
+		netDial = dialer.DialContext
+	}
+
+	var proxyURL *url.URL
+	if d.Proxy != nil {
+		target := *u
+		target.Scheme = strings.Replace(u.Scheme, "ws", "http", 1)
+		var err error
+		if proxyURL, err = d.Proxy(&http.Request{Method: http.MethodGet, URL: &target, Host: u.Host, Header: make(http.Header)}); err != nil {
+			return nil, err
+		}
+	}
+	if proxyURL == nil {
+		return netDial(ctx, "tcp", addr)
+	}
+
+	proxyAddr := proxyURL.Host
+	switch {
+	case proxyURL.Scheme != "http" && proxyURL.Scheme != "https":
+		return nil, fmt.Errorf("websocket: unsupported proxy scheme %q", proxyURL.Scheme)
+	case proxyURL.Port() == "" && proxyURL.Scheme == "http":
+		proxyAddr = net.JoinHostPort(proxyURL.Hostname(), "80")
+	case proxyURL.Port() == "":
+		proxyAddr = net.JoinHostPort(proxyURL.Hostname(), "443")
+	}
+	conn, err := netDial(ctx, "tcp", proxyAddr)
+	if err != nil {
+		return nil, err
+	}
+	if proxyURL.Scheme == "https" {
+		if conn, err = d.tlsClient(ctx, conn, proxyURL.Hostname()); err != nil {
+			return nil, err
+		}
+	}
+	if err := connect(ctx, conn, proxyURL, addr); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	return conn, nil
+}
+
+// connect asks the proxy at the other end of conn to open a tunnel to addr.
+func connect(ctx context.Context, conn net.Conn, proxyURL *url.URL, addr string) error {
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+		defer conn.SetDeadline(time.Time{})
+	}
+	req := &http.Request{
+		Method: http.MethodConnect,
+		URL:    &url.URL{Opaque: addr},
+		Host:   addr,
+		Header: make(http.Header),
+	}
+	if user := proxyURL.User; user != nil {
+		password, _ := user.Password()
+		credentials := base64.StdEncoding.EncodeToString([]byte(user.Username() + ":" + password))
+		req.Header.Set("Proxy-Authorization", "Basic "+credentials)
+	}
+	if err := req.Write(conn); err != nil {
+		return err
+	}
+
+	reader := bufio.NewReader(conn)
+	resp, err := http.ReadResponse(reader, req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("websocket: proxy refused to connect: %s", resp.Status)
+	}
+	if reader.Buffered() > 0 {
+		return errors.New("websocket: proxy sent data before the tunnel was used")
+	}
+	return nil
+}
+
+// tlsClient starts a TLS session with serverName over conn.
+func (d *Dialer) tlsClient(ctx context.Context, conn net.Conn, serverName string) (net.Conn, error) {
+	config := &tls.Config{}
+	if d.TLSClientConfig != nil {
+		config = d.TLSClientConfig.Clone()
+	}
+	if config.ServerName == "" {
+		config.ServerName = serverName
+	}
+	// The handshake is HTTP/1.1, whatever protocols the configuration offers for HTTP.
+	config.NextProtos = nil
+	tlsConn := tls.Client(conn, config)
+	if err := tlsConn.HandshakeContext(ctx); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	return tlsConn, nil
+}
+
+// handshake performs the opening handshake over conn.
+func (d *Dialer) handshake(ctx context.Context, conn net.Conn, u *url.URL, header http.Header) (*Conn, *http.Response, error) {
+	if u.Scheme == "wss" {
+		tlsConn, err := d.tlsClient(ctx, conn, u.Hostname())
+		if err != nil {
+			return nil, nil, err
+		}
+		conn = tlsConn
+	}
+
+	keyBytes := make([]byte, 16)
+	if _, err := rand.Read(keyBytes); err != nil {
+		return nil, nil, err
+	}
+	key := base64.StdEncoding.EncodeToString(keyBytes)
+
+	req := &http.Request{
+		Method:     http.MethodGet,
+		URL:        u,
+		Host:       u.Host,
+		Header:     make(http.Header),
+		Proto:      "HTTP/1.1",
+		ProtoMajor: 1,
+		ProtoMinor: 1,
+	}
+	for k, v := range header {
+		req.Header[k] = v
+	}
+	req.Header.Set("Upgrade", "websocket")
+	req.Header.Set("Connection", "Upgrade")
+	req.Header.Set("Sec-WebSocket-Key", key)
+	req.Header.Set("Sec-WebSocket-Version", "13")
+	if err := req.Write(conn); err != nil {
+		return nil, nil, err
+	}
+
+	reader := bufio.NewReader(conn)
+	resp, err := http.ReadResponse(reader, req)
+	if err != nil {
+		return nil, nil, err
+	}
+	if resp.StatusCode != http.StatusSwitchingProtocols {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1<<16))
+		resp.Body = io.NopCloser(bytes.NewReader(body))
+		return nil, resp, ErrBadHandshake
+	}
+	if resp.Header.Get("Sec-WebSocket-Accept") != acceptKey(key) {
+		return nil, resp, fmt.Errorf("%w: invalid Sec-WebSocket-Accept", ErrBadHandshake)
+	}
+	return &Conn{conn: conn, reader: reader, client: true}, resp, nil
+}
+
+// acceptKey computes the Sec-WebSocket-Accept value for a handshake key.
+func acceptKey(key string) string {
+	sum := sha1.Sum([]byte(key + acceptGUID))
+	return base64.StdEncoding.EncodeToString(sum[:])
+}
+
+// Upgrade upgrades an HTTP server request to a WebSocket connection.
+func Upgrade(w http.ResponseWriter, r *http.Request) (*Conn, error) {
+	if !strings.EqualFold(r.Header.Get("Upgrade"), "websocket") || r.Header.Get("Sec-WebSocket-Version") != "13" {
+		http.Error(w, "websocket upgrade required", http.StatusBadRequest)
+		return nil, fmt.Errorf("%w: not a websocket request", ErrBadHandshake)
+	}
+	key := r.Header.Get("Sec-WebSocket-Key")
+	if key == "" {
+		http.Error(w, "missing Sec-WebSocket-Key", http.StatusBadRequest)
+		return nil, fmt.Errorf("%w: missing key", ErrBadHandshake)
+	}
+
+	hijacker, ok := w.(http.Hijacker)
+	if !ok {
+		return nil, errors.New("websocket: response does not support hijacking")
+	}
+	conn, rw, err := hijacker.Hijack()
+	if err != nil {
+		return nil, err
+	}
+
+	response := "HTTP/1.1 101 Switching Protocols\r\n" +
+		"Upgrade: websocket\r\n" +
+		"Connection: Upgrade\r\n" +
+		"Sec-WebSocket-Accept: " + acceptKey(key) + "\r\n\r\n"
+	if _, err := conn.Write([]byte(response)); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	return &Conn{conn: conn, reader: rw.Reader}, nil
+}
+
+// ReadMessage reads the next data message, answering pings and close frames as they arrive.
+// When the peer closes the connection, a *CloseError is returned.
+func (c *Conn) ReadMessage() (MessageType, []byte, error) {
+	var (
+		messageType MessageType
+		data        []byte
+	)
+	for {
+		fin, opcode, payload, err := c.readFrame()
+		if err != nil {
+			return 0, nil, err
+		}
+
+		switch opcode {
+		case opPing:
+			if err := c.writeFrame(opPong, payload); err != nil {
+				return 0, nil, err
+			}
+			continue
+		case opPong:
+			continue
+		case opClose:
+			closeErr := &CloseError{Code: CloseNoStatus}
+			if len(payload) >= 2 {
+				closeErr.Code = int(binary.BigEndian.Uint16(payload))
+				closeErr.Text = string(payload[2:])
+			}
+			c.writeClose(closeErr.Code, "")
+			c.conn.Close()
+			return 0, nil, closeErr
+		case opContinuation:
+			if messageType == 0 {
+				return 0, nil, c.fail("unexpected continuation frame")
+			}
+		case byte(TextMessage), byte(BinaryMessage):
+			if messageType != 0 {
+				return 0, nil, c.fail("expected continuation frame")
+			}
+			messageType = MessageType(opcode)
+		default:
+			return 0, nil, c.fail(fmt.Sprintf("unknown opcode %d", opcode))
+		}
+
+		if len(data)+len(payload) > MaxMessageSize {
+			return 0, nil, c.fail("message too large")
+		}
+		data = append(data, payload...)
+		if fin {
+			return messageType, data, nil
+		}
+	}
+}
+
+// readFrame reads a single frame, unmasking its payload.
+func (c *Conn) readFrame() (fin bool, opcode byte, payload []byte, err error) {
+	var header [2]byte
+	if _, err := io.ReadFull(c.reader, header[:]); err != nil {
+		return false, 0, nil, err
+	}
+	fin = header[0]&0x80 != 0
+	opcode = header[0] & 0x0F
+	masked := header[1]&0x80 != 0
+
+	length := uint64(header[1] & 0x7F)
+	switch length {
+	case 126:
+		var ext [2]byte
+		if _, err := io.ReadFull(c.reader, ext[:]); err != nil {
+			return false, 0, nil, err
+		}
+		length = uint64(binary.BigEndian.Uint16(ext[:]))
+	case 127:
+		var ext [8]byte
+		if _, err := io.ReadFull(c.reader, ext[:]); err != nil {
+			return false, 0, nil, err
+		}
+		length = binary.BigEndian.Uint64(ext[:])
+	}
+	if length > MaxMessageSize {
+		return false, 0, nil, c.fail("frame too large")
+	}
+
+	var mask [4]byte
+	if masked {
+		if _, err := io.ReadFull(c.reader, mask[:]); err != nil {
+			return false, 0, nil, err
+		}
+	}
+	payload = make([]byte, length)
+	if _, err := io.ReadFull(c.reader, payload); err != nil {
+		return false, 0, nil, err
+	}
+	if masked {
+		maskBytes(mask, payload)
+	}
+	return fin, opcode, payload, nil
+}
+
+// fail closes the connection after a protocol error.
+func (c *Conn) fail(reason string) error {
+	c.writeClose(CloseProtocolError, reason)
+	c.conn.Close()
+	return fmt.Errorf("websocket: protocol error: %s", reason)
+}
+
+// WriteMessage sends a data message in a single frame.
+func (c *Conn) WriteMessage(messageType MessageType, data []byte) error {
+	return c.writeFrame(byte(messageType), data)
+}
+
+// Ping sends a ping frame.
+func (c *Conn) Ping(data []byte) error {
+	return c.writeFrame(opPing, data)
+}
+
+// writeFrame sends a single final frame with the given opcode.
+func (c *Conn) writeFrame(opcode byte, payload []byte) error {
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+	if c.closed {
+		return net.ErrClosed
+	}
+	if opcode == opClose {
+		c.closed = true
+	}
+
+	frame := make([]byte, 0, 14+len(payload))
+	frame = append(frame, 0x80|opcode)
+	maskBit := byte(0)
+	if c.client {
+		maskBit = 0x80
+	}
+	switch {
+	case len(payload) < 126:
+		frame = append(frame, maskBit|byte(len(payload)))
+	case len(payload) <= 0xFFFF:
+		frame = append(frame, maskBit|126)
+		frame = binary.BigEndian.AppendUint16(frame, uint16(len(payload)))
+	default:
+		frame = append(frame, maskBit|127)
+		frame = binary.BigEndian.AppendUint64(frame, uint64(len(payload)))
+	}
+
+	if c.client {
+		var mask [4]byte
+		if _, err := rand.Read(mask[:]); err != nil {
+			return err
+		}
+		frame = append(frame, mask[:]...)
+		start := len(frame)
+		frame = append(frame, payload...)
+		maskBytes(mask, frame[start:])
+	} else {
+		frame = append(frame, payload...)
+	}
+
+	_, err := c.conn.Write(frame)
+	return err
+}
+
+// writeClose sends a close frame, unless one has been sent already.
+func (c *Conn) writeClose(code int, reason string) {
+	payload := binary.BigEndian.AppendUint16(nil, uint16(code))
+	_ = c.writeFrame(opClose, append(payload, reason...))
+}
+
+// Close sends a normal close frame and closes the underlying connection without waiting
+// for the peer's reply.
+func (c *Conn) Close() error {
+	c.writeClose(CloseNormal, "")
+	return c.conn.Close()
+}
+
+// NetConn returns the underlying connection. Closing it drops the connection abruptly.
+func (c *Conn) NetConn() net.Conn {
+	return c.conn
+}
+
+// maskBytes applies the masking key to data in place.
+func maskBytes(mask [4]byte, data []byte) {
+	for i := range data {
+		data[i] ^= mask[i%4]
+	}
+}
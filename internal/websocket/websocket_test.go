@@ -0,0 +1,183 @@
+package websocket
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newEchoServer returns the ws:// URL of a server that echoes every message back.
+func newEchoServer(t *testing.T) string {
+	t.Helper()
+	s := httptest.NewServer(echoHandler)
+	t.Cleanup(s.Close)
+	return "ws" + strings.TrimPrefix(s.URL, "http")
+}
+
+// echoHandler upgrades requests to WebSocket connections that echo every message back.
+var echoHandler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+	if r.Header.Get("X-Reject") != "" {
+		http.Error(w, `{"error":{"message":"rejected"}}`, http.StatusForbidden)
+		return
+	}
+	conn, err := Upgrade(w, r)
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+	for {
+		messageType, data, err := conn.ReadMessage()
+		if err != nil {
+			return
+		}
+		if err := conn.WriteMessage(messageType, data); err != nil {
+			return
+		}
+	}
+})
+
+// newConnectProxy returns the URL of an HTTP proxy that tunnels CONNECT requests, and the
+// number of tunnels it has opened.
+func newConnectProxy(t *testing.T) (*url.URL, *atomic.Int32) {
+	t.Helper()
+	var tunnels atomic.Int32
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodConnect {
+			http.Error(w, "CONNECT required", http.StatusMethodNotAllowed)
+			return
+		}
+		upstream, err := net.Dial("tcp", r.Host)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadGateway)
+			return
+		}
+		tunnels.Add(1)
+		conn, _, err := w.(http.Hijacker).Hijack()
+		require.NoError(t, err)
+		conn.Write([]byte("HTTP/1.1 200 Connection established\r\n\r\n"))
+		go func() {
+			io.Copy(upstream, conn)
+			upstream.Close()
+		}()
+		io.Copy(conn, upstream)
+		conn.Close()
+	}))
+	t.Cleanup(s.Close)
+	proxyURL, err := url.Parse(s.URL)
+	require.NoError(t, err)
+	return proxyURL, &tunnels
+}
+
+func TestEcho(t *testing.T) {
+	conn, resp, err := Dial(context.Background(), newEchoServer(t), nil)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusSwitchingProtocols, resp.StatusCode)
+	defer conn.Close()
+
+	for _, size := range []int{0, 5, 200, 70000} {
+		message := strings.Repeat("x", size)
+		require.NoError(t, conn.WriteMessage(TextMessage, []byte(message)))
+		messageType, data, err := conn.ReadMessage()
+		require.NoError(t, err)
+		assert.Equal(t, TextMessage, messageType)
+		assert.Equal(t, message, string(data))
+	}
+
+	// Pings are answered without surfacing as messages.
+	require.NoError(t, conn.Ping([]byte("hi")))
+	require.NoError(t, conn.WriteMessage(BinaryMessage, []byte{1, 2}))
+	messageType, data, err := conn.ReadMessage()
+	require.NoError(t, err)
+	assert.Equal(t, BinaryMessage, messageType)
+	assert.Equal(t, []byte{1, 2}, data)
+}
+
+func TestDialerProxy(t *testing.T) {
+	proxyURL, tunnels := newConnectProxy(t)
+	dialer := &Dialer{Proxy: http.ProxyURL(proxyURL)}
+
+	conn, _, err := dialer.Dial(context.Background(), newEchoServer(t), nil)
+	require.NoError(t, err)
+	defer conn.Close()
+	assert.Equal(t, int32(1), tunnels.Load())
+
+	require.NoError(t, conn.WriteMessage(TextMessage, []byte("hello")))
+	_, data, err := conn.ReadMessage()
+	require.NoError(t, err)
+	assert.Equal(t, "hello", string(data))
+}
+
+func TestDialerTLS(t *testing.T) {
+	server := httptest.NewTLSServer(echoHandler)
+	defer server.Close()
+	wsURL := "wss" + strings.TrimPrefix(server.URL, "https")
+
+	// The test server's certificate is only trusted with its own TLS config.
+	_, _, err := Dial(context.Background(), wsURL, nil)
+	assert.Error(t, err)
+
+	var dials atomic.Int32
+	dialer := &Dialer{
+		NetDialContext: func(ctx context.Context, network, addr string) (net.Conn, error) {
+			dials.Add(1)
+			return (&net.Dialer{}).DialContext(ctx, network, addr)
+		},
+		TLSClientConfig: server.Client().Transport.(*http.Transport).TLSClientConfig,
+	}
+	conn, _, err := dialer.Dial(context.Background(), wsURL, nil)
+	require.NoError(t, err)
+	defer conn.Close()
+	assert.Equal(t, int32(1), dials.Load())
+
+	require.NoError(t, conn.WriteMessage(TextMessage, []byte("secure")))
+	_, data, err := conn.ReadMessage()
+	require.NoError(t, err)
+	assert.Equal(t, "secure", string(data))
+}
+
+func TestFragmentedMessage(t *testing.T) {
+	serverConn := make(chan *Conn, 1)
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := Upgrade(w, r)
+		require.NoError(t, err)
+		serverConn <- conn
+	}))
+	defer s.Close()
+
+	client, _, err := Dial(context.Background(), "ws"+strings.TrimPrefix(s.URL, "http"), nil)
+	require.NoError(t, err)
+	defer client.Close()
+	server := <-serverConn
+
+	// Write "hello" as a text frame and a continuation frame.
+	_, err = server.NetConn().Write([]byte{0x01, 3, 'h', 'e', 'l', 0x80, 2, 'l', 'o'})
+	require.NoError(t, err)
+	_, data, err := client.ReadMessage()
+	require.NoError(t, err)
+	assert.Equal(t, "hello", string(data))
+
+	require.NoError(t, server.Close())
+	_, _, err = client.ReadMessage()
+	var closeErr *CloseError
+	require.ErrorAs(t, err, &closeErr)
+	assert.Equal(t, CloseNormal, closeErr.Code)
+}
+
+func TestDialRejected(t *testing.T) {
+	_, resp, err := Dial(context.Background(), newEchoServer(t), http.Header{"X-Reject": {"1"}})
+	assert.ErrorIs(t, err, ErrBadHandshake)
+	require.NotNil(t, resp)
+	assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+
+	_, _, err = Dial(context.Background(), "http://example.com", nil)
+	assert.ErrorContains(t, err, "unsupported scheme")
+}
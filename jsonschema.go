@@ -0,0 +1,259 @@
+package sarvam
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// JSONSchema is the subset of JSON Schema used to describe structured outputs and tool
+// arguments.
+type JSONSchema struct {
+	Type                 string                 `json:"type,omitempty"`
+	Description          string                 `json:"description,omitempty"`
+	Format               string                 `json:"format,omitempty"`
+	Enum                 []any                  `json:"enum,omitempty"`
+	Properties           map[string]*JSONSchema `json:"properties,omitempty"`
+	Required             []string               `json:"required,omitempty"`
+	AdditionalProperties any                    `json:"additionalProperties,omitempty"` // bool or *JSONSchema
+	Items                *JSONSchema            `json:"items,omitempty"`
+	Nullable             bool                   `json:"nullable,omitempty"` // Whether null is accepted in place of the value
+}
+
+// JSONSchemaFor derives a JSON schema from the Go type T.
+//
+// Struct fields are named after their json tags, and fields without omitempty are required.
+// Pointers, slices and maps are nullable, since encoding/json encodes their nil values as null.
+// A description tag sets the field's description and an enum tag lists the allowed values,
+// separated by commas:
+//
+//	type Review struct {
+//		Sentiment string `json:"sentiment" enum:"positive,negative,neutral"`
+//		Summary   string `json:"summary" description:"One sentence summary"`
+//	}
+func JSONSchemaFor[T any]() *JSONSchema {
+	return schemaForType(reflect.TypeFor[T](), nil)
+}
+
+var (
+	timeType       = reflect.TypeFor[time.Time]()
+	rawMessageType = reflect.TypeFor[json.RawMessage]()
+)
+
+// schemaForType builds the schema of t. Types in seen are being built further up the
+// stack; recursive references to them are left unconstrained.
+func schemaForType(t reflect.Type, seen []reflect.Type) *JSONSchema {
+	schema := schemaForValueType(t, seen)
+	switch t.Kind() {
+	case reflect.Pointer, reflect.Slice, reflect.Map:
+		schema.Nullable = true
+	}
+	return schema
+}
+
+// schemaForValueType builds the schema of the non-null values of t.
+func schemaForValueType(t reflect.Type, seen []reflect.Type) *JSONSchema {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	switch t {
+	case timeType:
+		return &JSONSchema{Type: "string", Format: "date-time"}
+	case rawMessageType:
+		return &JSONSchema{}
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return &JSONSchema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return &JSONSchema{Type: "integer"}
+	case reflect.Float32, reflect.Float64:
+		return &JSONSchema{Type: "number"}
+	case reflect.String:
+		return &JSONSchema{Type: "string"}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return &JSONSchema{Type: "string"} // encoded as base64
+		}
+		return &JSONSchema{Type: "array", Items: schemaForType(t.Elem(), seen)}
+	case reflect.Map:
+		return &JSONSchema{Type: "object", AdditionalProperties: schemaForType(t.Elem(), seen)}
+	case reflect.Struct:
+		if slices.Contains(seen, t) {
+			return &JSONSchema{}
+		}
+		schema := &JSONSchema{Type: "object", Properties: make(map[string]*JSONSchema), AdditionalProperties: false}
+		addStructFields(schema, t, append(seen, t))
+		return schema
+	}
+	return &JSONSchema{}
+}
+
+// addStructFields adds the fields of struct type t to schema, flattening embedded structs
+// the way encoding/json does.
+func addStructFields(schema *JSONSchema, t reflect.Type, seen []reflect.Type) {
+	for i := range t.NumField() {
+		field := t.Field(i)
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, opts, _ := strings.Cut(tag, ",")
+
+		fieldType := field.Type
+		for fieldType.Kind() == reflect.Pointer {
+			fieldType = fieldType.Elem()
+		}
+		if field.Anonymous && name == "" && fieldType.Kind() == reflect.Struct {
+			addStructFields(schema, fieldType, seen)
+			continue
+		}
+		if !field.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+
+		property := schemaForType(field.Type, seen)
+		property.Description = field.Tag.Get("description")
+		if enum := field.Tag.Get("enum"); enum != "" {
+			for _, value := range strings.Split(enum, ",") {
+				property.Enum = append(property.Enum, value)
+			}
+		}
+		schema.Properties[name] = property
+		if !slices.Contains(strings.Split(opts, ","), "omitempty") {
+			schema.Required = append(schema.Required, name)
+		}
+	}
+}
+
+// SchemaValidationError is returned when a value does not match a JSON schema.
+type SchemaValidationError struct {
+	Problems []string // One entry per violation, prefixed with the path of the offending value
+}
+
+func (e *SchemaValidationError) Error() string {
+	return "value does not match schema: " + strings.Join(e.Problems, "; ")
+}
+
+// Validate checks a decoded JSON value against the schema. The value should be decoded
+// into an interface{} with json.Decoder.UseNumber so that integers can be told apart.
+func (s *JSONSchema) Validate(value any) error {
+	var problems []string
+	s.validate("$", value, &problems)
+	if len(problems) > 0 {
+		return &SchemaValidationError{Problems: problems}
+	}
+	return nil
+}
+
+func (s *JSONSchema) validate(path string, value any, problems *[]string) {
+	report := func(format string, args ...any) {
+		*problems = append(*problems, path+": "+fmt.Sprintf(format, args...))
+	}
+	if value == nil && s.Nullable {
+		return
+	}
+
+	if len(s.Enum) > 0 && !slices.ContainsFunc(s.Enum, func(e any) bool { return fmt.Sprint(e) == fmt.Sprint(value) }) {
+		report("must be one of %v", s.Enum)
+		return
+	}
+
+	switch s.Type {
+	case "":
+		return
+	case "object":
+		object, ok := value.(map[string]any)
+		if !ok {
+			report("expected object, got %s", jsonTypeName(value))
+			return
+		}
+		for _, name := range s.Required {
+			if _, ok := object[name]; !ok {
+				report("missing required property %q", name)
+			}
+		}
+		for name, v := range object {
+			if property, ok := s.Properties[name]; ok {
+				property.validate(path+"."+name, v, problems)
+				continue
+			}
+			switch additional := s.AdditionalProperties.(type) {
+			case bool:
+				if !additional {
+					report("unexpected property %q", name)
+				}
+			case *JSONSchema:
+				additional.validate(path+"."+name, v, problems)
+			}
+		}
+	case "array":
+		array, ok := value.([]any)
+		if !ok {
+			report("expected array, got %s", jsonTypeName(value))
+			return
+		}
+		if s.Items != nil {
+			for i, v := range array {
+				s.Items.validate(path+"["+strconv.Itoa(i)+"]", v, problems)
+			}
+		}
+	case "string":
+		if _, ok := value.(string); !ok {
+			report("expected string, got %s", jsonTypeName(value))
+		}
+	case "boolean":
+		if _, ok := value.(bool); !ok {
+			report("expected boolean, got %s", jsonTypeName(value))
+		}
+	case "number":
+		if !isJSONNumber(value) {
+			report("expected number, got %s", jsonTypeName(value))
+		}
+	case "integer":
+		if !isJSONNumber(value) {
+			report("expected integer, got %s", jsonTypeName(value))
+		} else if n, ok := value.(json.Number); ok {
+			if _, err := n.Int64(); err != nil {
+				report("expected integer, got %s", n)
+			}
+		} else if f, ok := value.(float64); ok && f != float64(int64(f)) {
+			report("expected integer, got %v", f)
+		}
+	}
+}
+
+func isJSONNumber(value any) bool {
+	switch value.(type) {
+	case json.Number, float64:
+		return true
+	}
+	return false
+}
+
+func jsonTypeName(value any) string {
+	switch value.(type) {
+	case nil:
+		return "null"
+	case map[string]any:
+		return "object"
+	case []any:
+		return "array"
+	case string:
+		return "string"
+	case bool:
+		return "boolean"
+	case json.Number, float64:
+		return "number"
+	}
+	return fmt.Sprintf("%T", value)
+}
@@ -1,9 +1,13 @@
 package sarvam
 
+import "slices"
+
 // ChatCompletionModel specifies the model to use for chat completions.
 type ChatCompletionModel string
 
 var (
+	// Deprecated: bulbul is a text-to-speech model and cannot complete chats; requests
+	// using it are rejected. Use TextToSpeechModelBulbulV2 with TextToSpeech instead.
 	ChatCompletionModelBulbulV2 ChatCompletionModel = "bulbul:v2"
 	ChatCompletionModelSarvamM  ChatCompletionModel = "sarvam-m"
 )
@@ -19,6 +23,7 @@ var (
 type SpeechToTextModel string
 
 var (
+	// Deprecated: Use SpeechToTextModelSaarikaV2dot5.
 	SpeechToTextModelSaarikaV1     SpeechToTextModel = "saarika:v1"
 	SpeechToTextModelSaarikaV2     SpeechToTextModel = "saarika:v2"
 	SpeechToTextModelSaarikaV2dot5 SpeechToTextModel = "saarika:v2.5"
@@ -29,6 +34,7 @@ var (
 type SpeechToTextTranslateModel string
 
 var (
+	// Deprecated: Use SpeechToTextTranslateModelSaarasV2dot5.
 	SpeechToTextTranslateModelSaarasV1     SpeechToTextTranslateModel = "saaras:v1"
 	SpeechToTextTranslateModelSaarasV2     SpeechToTextTranslateModel = "saaras:v2"
 	SpeechToTextTranslateModelSaarasV2dot5 SpeechToTextTranslateModel = "saaras:v2.5"
@@ -42,3 +48,125 @@ var (
 	TranslationModelMayuraV1        TranslationModel = "mayura:v1"
 	TranslationModelSarvamTranslate TranslationModel = "sarvam-translate:v1"
 )
+
+// ModelInfo describes what a model accepts. Requests are validated against it before they
+// are sent. Empty lists and zero limits leave the parameter unchecked.
+type ModelInfo struct {
+	ID             string     // Model name sent to the API; empty for endpoints without a choice of model
+	Endpoint       string     // Endpoint serving the model, such as EndpointTextToSpeech
+	MaxInputLength int        // Maximum input length, in characters
+	Languages      []Language // Supported languages
+	Speakers       []Speaker  // Supported text-to-speech voices
+	SampleRates    []int      // Supported sample rates of the audio produced or, when streaming, received
+	Deprecated     bool
+	Replacement    string // Model to use instead of a deprecated one
+}
+
+// SupportsLanguage reports whether the model accepts language.
+func (m ModelInfo) SupportsLanguage(language Language) bool {
+	return len(m.Languages) == 0 || slices.Contains(m.Languages, language)
+}
+
+// SupportsSpeaker reports whether the model accepts speaker.
+func (m ModelInfo) SupportsSpeaker(speaker Speaker) bool {
+	return len(m.Speakers) == 0 || slices.Contains(m.Speakers, speaker)
+}
+
+// SupportsSampleRate reports whether the model accepts the sample rate, in Hz.
+func (m ModelInfo) SupportsSampleRate(sampleRate int) bool {
+	return len(m.SampleRates) == 0 || slices.Contains(m.SampleRates, sampleRate)
+}
+
+// Languages supported by the first generation of models: English and ten Indic languages.
+var coreLanguages = []Language{
+	LanguageBengali, LanguageEnglish, LanguageGujarati, LanguageHindi, LanguageKannada, LanguageMalayalam,
+	LanguageMarathi, LanguageOdia, LanguagePunjabi, LanguageTamil, LanguageTelugu,
+}
+
+// allLanguages are the 22 scheduled languages of India and English.
+var allLanguages = []Language{
+	LanguageAssamese, LanguageBengali, LanguageBodo, LanguageDogri, LanguageEnglish, LanguageGujarati,
+	LanguageHindi, LanguageKannada, LanguageKashmiri, LanguageKonkani, LanguageMaithili, LanguageMalayalam,
+	LanguageManipuri, LanguageMarathi, LanguageNepali, LanguageOdia, LanguagePunjabi, LanguageSanskrit,
+	LanguageSantali, LanguageSindhi, LanguageTamil, LanguageTelugu, LanguageUrdu,
+}
+
+var bulbulV2Speakers = []Speaker{
+	SpeakerAnushka, SpeakerManisha, SpeakerVidya, SpeakerArya, SpeakerAbhilash, SpeakerKarun, SpeakerHitesh,
+}
+
+// modelRegistry describes every model known to the client.
+var modelRegistry = []ModelInfo{
+	{Endpoint: EndpointTextLID, MaxInputLength: 1000},
+	{Endpoint: EndpointTransliterate, MaxInputLength: 1000, Languages: coreLanguages},
+
+	{ID: string(TranslationModelMayuraV1), Endpoint: EndpointTranslate, MaxInputLength: 1000, Languages: coreLanguages},
+	{ID: string(TranslationModelSarvamTranslate), Endpoint: EndpointTranslate, MaxInputLength: 2000, Languages: allLanguages},
+
+	{
+		ID: string(TextToSpeechModelBulbulV2), Endpoint: EndpointTextToSpeech, MaxInputLength: 1500,
+		Languages: coreLanguages, Speakers: bulbulV2Speakers, SampleRates: []int{8000, 16000, 22050, 24000},
+	},
+	{
+		ID: string(TextToSpeechModelBulbulV2), Endpoint: EndpointTextToSpeechStream, MaxInputLength: 1500,
+		Languages: coreLanguages, Speakers: bulbulV2Speakers, SampleRates: []int{8000, 16000, 22050, 24000},
+	},
+
+	{ID: string(SpeechToTextModelSaarikaV1), Endpoint: EndpointSpeechToText, Languages: coreLanguages, Deprecated: true, Replacement: string(SpeechToTextModelSaarikaV2dot5)},
+	{ID: string(SpeechToTextModelSaarikaV2), Endpoint: EndpointSpeechToText, Languages: coreLanguages},
+	{ID: string(SpeechToTextModelSaarikaV2dot5), Endpoint: EndpointSpeechToText, Languages: coreLanguages},
+	{ID: string(SpeechToTextModelSaarikaFlash), Endpoint: EndpointSpeechToText, Languages: coreLanguages},
+	{ID: string(SpeechToTextModelSaarikaV2dot5), Endpoint: EndpointSpeechToTextStream, Languages: coreLanguages, SampleRates: []int{8000, 16000}},
+
+	{ID: string(SpeechToTextTranslateModelSaarasV1), Endpoint: EndpointSpeechToTextTranslate, Languages: coreLanguages, Deprecated: true, Replacement: string(SpeechToTextTranslateModelSaarasV2dot5)},
+	{ID: string(SpeechToTextTranslateModelSaarasV2), Endpoint: EndpointSpeechToTextTranslate, Languages: coreLanguages},
+	{ID: string(SpeechToTextTranslateModelSaarasV2dot5), Endpoint: EndpointSpeechToTextTranslate, Languages: coreLanguages},
+	{ID: string(SpeechToTextTranslateModelSaarasFlash), Endpoint: EndpointSpeechToTextTranslate, Languages: coreLanguages},
+
+	{ID: string(ChatCompletionModelSarvamM), Endpoint: EndpointChatCompletions},
+}
+
+// defaultModels are the models requests are validated against when they do not name one.
+// Translation assumes its most permissive model, since the API may pick either.
+var defaultModels = map[string]string{
+	EndpointTranslate:             string(TranslationModelSarvamTranslate),
+	EndpointTextToSpeech:          string(TextToSpeechModelBulbulV2),
+	EndpointTextToSpeechStream:    string(TextToSpeechModelBulbulV2),
+	EndpointSpeechToText:          string(SpeechToTextModelSaarikaV2dot5),
+	EndpointSpeechToTextStream:    string(SpeechToTextModelSaarikaV2dot5),
+	EndpointSpeechToTextTranslate: string(SpeechToTextTranslateModelSaarasV2dot5),
+}
+
+// Models returns the descriptions of all models known to the client.
+func Models() []ModelInfo {
+	models := make([]ModelInfo, len(modelRegistry))
+	for i, info := range modelRegistry {
+		models[i] = info.clone()
+	}
+	return models
+}
+
+// LookupModel returns the description of the model with the given ID served by endpoint.
+// Endpoints without a choice of model are described under an empty ID.
+func LookupModel(endpoint, id string) (ModelInfo, bool) {
+	info, ok := lookupModel(endpoint, id)
+	return info.clone(), ok
+}
+
+// lookupModel is like LookupModel but returns the registry entry itself.
+func lookupModel(endpoint, id string) (ModelInfo, bool) {
+	for _, info := range modelRegistry {
+		if info.Endpoint == endpoint && info.ID == id {
+			return info, true
+		}
+	}
+	return ModelInfo{}, false
+}
+
+// clone returns a copy of m that does not share the registry's lists.
+func (m ModelInfo) clone() ModelInfo {
+	m.Languages = slices.Clone(m.Languages)
+	m.Speakers = slices.Clone(m.Speakers)
+	m.SampleRates = slices.Clone(m.SampleRates)
+	return m
+}
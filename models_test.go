@@ -0,0 +1,148 @@
+package sarvam
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestModelRegistry(t *testing.T) {
+	registered := func(endpoint string, id string) bool {
+		_, ok := LookupModel(endpoint, id)
+		return ok
+	}
+	for _, model := range []TranslationModel{TranslationModelMayuraV1, TranslationModelSarvamTranslate} {
+		assert.True(t, registered(EndpointTranslate, string(model)), model)
+	}
+	assert.True(t, registered(EndpointTextToSpeech, string(TextToSpeechModelBulbulV2)))
+	for _, model := range []SpeechToTextModel{SpeechToTextModelSaarikaV1, SpeechToTextModelSaarikaV2, SpeechToTextModelSaarikaV2dot5, SpeechToTextModelSaarikaFlash} {
+		assert.True(t, registered(EndpointSpeechToText, string(model)), model)
+	}
+	for _, model := range []SpeechToTextTranslateModel{SpeechToTextTranslateModelSaarasV1, SpeechToTextTranslateModelSaarasV2, SpeechToTextTranslateModelSaarasV2dot5, SpeechToTextTranslateModelSaarasFlash} {
+		assert.True(t, registered(EndpointSpeechToTextTranslate, string(model)), model)
+	}
+	assert.True(t, registered(EndpointChatCompletions, string(ChatCompletionModelSarvamM)))
+	assert.False(t, registered(EndpointChatCompletions, string(ChatCompletionModelBulbulV2)))
+
+	for _, info := range Models() {
+		if info.Deprecated {
+			_, ok := LookupModel(info.Endpoint, info.Replacement)
+			assert.True(t, ok, "replacement of %s", info.ID)
+		}
+		if info.ID != "" && info.Endpoint != EndpointChatCompletions { // Chat requests must name a model
+			assert.Contains(t, defaultModels, info.Endpoint, "endpoint %s of %s has no default model", info.Endpoint, info.ID)
+		}
+	}
+
+	// Callers cannot modify the registry through the returned descriptions.
+	info, ok := LookupModel(EndpointTextToSpeech, string(TextToSpeechModelBulbulV2))
+	require.True(t, ok)
+	info.Speakers[0] = "nobody"
+	info, _ = LookupModel(EndpointTextToSpeech, string(TextToSpeechModelBulbulV2))
+	assert.True(t, info.SupportsSpeaker(SpeakerAnushka))
+	assert.False(t, info.SupportsSpeaker("nobody"))
+}
+
+func TestRequestsValidatedAgainstRegistry(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+	client := NewClient("test", WithBaseURL(server.URL))
+
+	tests := []struct {
+		name      string
+		call      func() error
+		parameter string
+	}{
+		{"translation language", func() error {
+			_, err := client.Translate("hello", LanguageEnglish, LanguageBodo, &TranslateParams{Model: &TranslationModelMayuraV1})
+			return err
+		}, "target_language_code"},
+		{"transliteration language", func() error {
+			_, err := client.Transliterate("hello", LanguageSanskrit, LanguageHindi, nil)
+			return err
+		}, "source_language_code"},
+		{"text-to-speech speaker", func() error {
+			_, err := client.TextToSpeech("hello", LanguageHindi, TextToSpeechParams{Speaker: Ptr(Speaker("nobody"))})
+			return err
+		}, "speaker"},
+		{"text-to-speech sample rate", func() error {
+			_, err := client.TextToSpeechStream("hello", LanguageHindi, TextToSpeechParams{SpeechSampleRate: Ptr(SpeechSampleRate(44100))})
+			return err
+		}, "speech_sample_rate"},
+		{"text-to-speech language", func() error {
+			_, err := client.TextToSpeech("hello", LanguageUrdu, TextToSpeechParams{})
+			return err
+		}, "target_language_code"},
+		{"speech-to-text language", func() error {
+			_, err := client.SpeechToText(strings.NewReader(testWAVHeader), SpeechToTextParams{Language: Ptr(LanguageDogri)})
+			return err
+		}, "language_code"},
+		{"speech-to-text model", func() error {
+			_, err := client.SpeechToText(strings.NewReader(testWAVHeader), SpeechToTextParams{Model: Ptr(SpeechToTextModel("saaras:v2.5"))})
+			return err
+		}, "model"},
+		{"speech-to-text job language", func() error {
+			_, err := client.CreateSpeechToTextJob(SpeechToTextParams{Language: Ptr(LanguageNepali)})
+			return err
+		}, "language_code"},
+		{"streaming sample rate", func() error {
+			_, err := client.SpeechToTextStream(SpeechToTextStreamParams{SampleRate: Ptr(44100)})
+			return err
+		}, "sample_rate"},
+		{"chat model", func() error {
+			_, err := client.ChatCompletion([]Message{NewUserMessage("hi")}, ChatCompletionModelBulbulV2, nil)
+			return err
+		}, "model"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.call()
+			assert.ErrorIs(t, err, ErrValidation)
+			var unsupported *UnsupportedError
+			require.ErrorAs(t, err, &unsupported)
+			assert.Equal(t, tt.parameter, unsupported.Parameter)
+		})
+	}
+	assert.Zero(t, requests.Load(), "invalid requests must not be sent")
+}
+
+func TestUnsupportedErrorMessages(t *testing.T) {
+	_, err := NewClient("test").ChatCompletion([]Message{NewUserMessage("hi")}, ChatCompletionModelBulbulV2, nil)
+	assert.EqualError(t, err, `model "bulbul:v2" is not supported by /v1/chat/completions`)
+
+	_, err = NewClient("test").TextToSpeech("hello", LanguageHindi, TextToSpeechParams{Speaker: Ptr(Speaker("nobody"))})
+	assert.EqualError(t, err, `speaker "nobody" is not supported by model "bulbul:v2"; supported values are anushka, manisha, vidya, arya, abhilash, karun, hitesh`)
+}
+
+func TestUnknownModelsAreNotValidated(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+	client := NewClient("test", WithBaseURL(server.URL))
+
+	// Models released after this client keep working, with the default model's input limit.
+	newModel := TranslationModel("mayura:v2")
+	_, err := client.Translate("hello", LanguageEnglish, LanguageBodo, &TranslateParams{Model: &newModel})
+	assert.ErrorIs(t, err, ErrServer)
+	_, err = client.Translate(strings.Repeat("a", 2001), LanguageEnglish, LanguageHindi, &TranslateParams{Model: &newModel})
+	var inputErr *ErrInputTooLong
+	require.ErrorAs(t, err, &inputErr)
+	assert.Equal(t, 2000, inputErr.MaxLength)
+	assert.ErrorIs(t, err, ErrValidation)
+
+	_, err = client.ChatCompletion([]Message{NewUserMessage("hi")}, ChatCompletionModel("sarvam-l"), nil)
+	assert.ErrorIs(t, err, ErrServer)
+	assert.EqualValues(t, 2, requests.Load())
+}
@@ -0,0 +1,121 @@
+package sarvam
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+func TestNewClientDefaults(t *testing.T) {
+	client := NewClient("test")
+	assert.Equal(t, defaultBaseURL, client.baseURL)
+	assert.Equal(t, http.DefaultClient, client.httpClient)
+	assert.Equal(t, defaultUserAgent, client.userAgent)
+}
+
+func TestNewClientOptions(t *testing.T) {
+	var got http.Header
+	httpTestServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r.Header.Clone()
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer httpTestServer.Close()
+
+	client := NewClient("test",
+		WithBaseURL(httpTestServer.URL),
+		WithUserAgent("my-service/1.0"),
+		WithHeader("X-Trace-Id", "abc"),
+		WithHeaders(http.Header{"X-Team": []string{"speech"}}),
+		WithHeader("api-subscription-key", "ignored"),
+	)
+	assert.Equal(t, httpTestServer.URL, client.baseURL)
+
+	resp, err := client.makeJsonHTTPRequest(context.Background(), http.MethodGet, client.baseURL+"/v1/test", nil)
+	assert.NoError(t, err)
+	resp.Body.Close()
+
+	assert.Equal(t, "my-service/1.0", got.Get("User-Agent"))
+	assert.Equal(t, "abc", got.Get("X-Trace-Id"))
+	assert.Equal(t, "speech", got.Get("X-Team"))
+	assert.Equal(t, "test", got.Get("api-subscription-key"))
+	assert.Equal(t, "application/json", got.Get("Content-Type"))
+}
+
+func TestNewClientHeadersNotShared(t *testing.T) {
+	var got []string
+	transport := roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		got = append(got, req.Header.Get("X-Trace-Id"))
+		req.Header["X-Trace-Id"][0] = "changed"
+		return &http.Response{StatusCode: http.StatusOK, Body: http.NoBody, Request: req}, nil
+	})
+	client := NewClient("test", WithTransport(transport), WithHeader("X-Trace-Id", "abc"))
+
+	for range 2 {
+		resp, err := client.makeJsonHTTPRequest(context.Background(), http.MethodGet, "http://example.invalid/v1/test", nil)
+		assert.NoError(t, err)
+		resp.Body.Close()
+	}
+	assert.Equal(t, []string{"abc", "abc"}, got)
+}
+
+func TestNewClientContentTypeHeader(t *testing.T) {
+	var got []string
+	transport := roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		got = append(got, req.Header.Get("Content-Type"))
+		return &http.Response{StatusCode: http.StatusOK, Body: http.NoBody, Request: req}, nil
+	})
+	client := NewClient("test", WithTransport(transport), WithHeader("Content-Type", "text/plain"))
+
+	resp, err := client.makeJsonHTTPRequest(context.Background(), http.MethodPost, "http://example.invalid/v1/test", map[string]string{})
+	assert.NoError(t, err)
+	resp.Body.Close()
+	resp, err = client.makeHTTPRequest(context.Background(), http.MethodGet, "http://example.invalid/v1/test", nil, "")
+	assert.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, []string{"application/json", "text/plain"}, got)
+}
+
+func TestNewClientTransportAndTimeout(t *testing.T) {
+	shared := &http.Client{}
+	called := false
+	transport := roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		called = true
+		return &http.Response{StatusCode: http.StatusOK, Body: http.NoBody, Request: req}, nil
+	})
+
+	client := NewClient("test", WithHTTPClient(shared), WithTransport(transport), WithTimeout(5*time.Second))
+	assert.NotSame(t, shared, client.httpClient)
+	assert.Nil(t, shared.Transport)
+	assert.Zero(t, shared.Timeout)
+	assert.Equal(t, 5*time.Second, client.httpClient.Timeout)
+
+	resp, err := client.makeJsonHTTPRequest(context.Background(), http.MethodGet, "http://example.invalid/v1/test", nil)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.True(t, called)
+}
+
+func TestNewClientWithHTTPClient(t *testing.T) {
+	shared := &http.Client{}
+	client := NewClient("test", WithHTTPClient(shared))
+	assert.Same(t, shared, client.httpClient)
+}
+
+func TestNewClientWithNilHTTPClient(t *testing.T) {
+	client := NewClient("test", WithHTTPClient(nil))
+	assert.Same(t, http.DefaultClient, client.httpClient)
+
+	client = NewClient("test", WithHTTPClient(nil), WithTimeout(5*time.Second))
+	assert.Equal(t, 5*time.Second, client.httpClient.Timeout)
+	assert.Zero(t, http.DefaultClient.Timeout)
+}
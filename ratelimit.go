@@ -0,0 +1,171 @@
+package sarvam
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrThrottled is returned when a client-side rate limit or concurrency cap configured
+// with FailFast would otherwise block the request.
+var ErrThrottled = errors.New("request throttled by client-side rate limit")
+
+// RateLimit configures client-side throttling of requests.
+// The zero value imposes no limits.
+type RateLimit struct {
+	// RequestsPerSecond is the sustained request rate. Zero means no rate limit.
+	RequestsPerSecond float64
+	// Burst is the number of requests that may be sent at once before the rate applies.
+	// Values below 1 are treated as 1.
+	Burst int
+	// MaxInFlight caps the number of concurrent requests. A request counts as in flight
+	// until its response body is closed. Zero means no cap.
+	MaxInFlight int
+	// FailFast makes requests return ErrThrottled instead of waiting for capacity.
+	FailFast bool
+}
+
+// WithRateLimit throttles requests to a single endpoint, such as EndpointTranslate.
+func WithRateLimit(endpoint string, limit RateLimit) Option {
+	return func(c *Client) {
+		if c.rateLimiters == nil {
+			c.rateLimiters = make(map[string]*rateLimiter)
+		}
+		c.rateLimiters[endpoint] = newRateLimiter(limit)
+	}
+}
+
+// WithDefaultRateLimit throttles requests to every endpoint that has no limit of its own.
+// The limit is shared by all of those endpoints.
+func WithDefaultRateLimit(limit RateLimit) Option {
+	return func(c *Client) {
+		c.defaultRateLimiter = newRateLimiter(limit)
+	}
+}
+
+// rateLimiter combines a token bucket with a semaphore capping requests in flight.
+type rateLimiter struct {
+	failFast bool
+
+	mu     sync.Mutex
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+
+	inFlight chan struct{}
+}
+
+func newRateLimiter(limit RateLimit) *rateLimiter {
+	l := &rateLimiter{
+		failFast: limit.FailFast,
+		rate:     limit.RequestsPerSecond,
+		burst:    float64(max(limit.Burst, 1)),
+	}
+	l.tokens = l.burst
+	if limit.MaxInFlight > 0 {
+		l.inFlight = make(chan struct{}, limit.MaxInFlight)
+	}
+	return l
+}
+
+// rateLimiterFor returns the limiter that applies to url, or nil if there is none.
+func (c *Client) rateLimiterFor(url string) *rateLimiter {
+	if c.rateLimiters != nil {
+		endpoint := strings.TrimPrefix(url, c.baseURL)
+		if i := strings.IndexByte(endpoint, '?'); i >= 0 {
+			endpoint = endpoint[:i]
+		}
+		if l, ok := c.rateLimiters[endpoint]; ok {
+			return l
+		}
+	}
+	return c.defaultRateLimiter
+}
+
+// acquire blocks until the request may be sent and returns a function that releases
+// its in-flight slot.
+func (l *rateLimiter) acquire(ctx context.Context) (release func(), err error) {
+	if err := l.wait(ctx); err != nil {
+		return nil, err
+	}
+	if l.inFlight == nil {
+		return func() {}, nil
+	}
+
+	if l.failFast {
+		select {
+		case l.inFlight <- struct{}{}:
+		default:
+			return nil, ErrThrottled
+		}
+	} else {
+		select {
+		case l.inFlight <- struct{}{}:
+		case <-ctx.Done():
+			return nil, contextError(ctx, ctx.Err())
+		}
+	}
+
+	var once sync.Once
+	return func() { once.Do(func() { <-l.inFlight }) }, nil
+}
+
+// wait takes a token from the bucket, waiting for one to become available if necessary.
+func (l *rateLimiter) wait(ctx context.Context) error {
+	if l.rate <= 0 {
+		return nil
+	}
+
+	l.mu.Lock()
+	now := time.Now()
+	if !l.last.IsZero() {
+		l.tokens = math.Min(l.burst, l.tokens+now.Sub(l.last).Seconds()*l.rate)
+	}
+	l.last = now
+	if l.tokens < 1 && l.failFast {
+		l.mu.Unlock()
+		return ErrThrottled
+	}
+	l.tokens--
+	delay := time.Duration(-l.tokens / l.rate * float64(time.Second))
+	l.mu.Unlock()
+
+	if delay <= 0 {
+		return nil
+	}
+	if err := sleep(ctx, delay); err != nil {
+		// Return the unused token so that abandoned requests do not slow down others.
+		l.mu.Lock()
+		l.tokens = math.Min(l.burst, l.tokens+1)
+		l.mu.Unlock()
+		return contextError(ctx, err)
+	}
+	return nil
+}
+
+// releaseOnClose wraps a response body so that release is called when it is closed.
+type releaseOnClose struct {
+	io.ReadCloser
+	release func()
+}
+
+func (r *releaseOnClose) Close() error {
+	err := r.ReadCloser.Close()
+	r.release()
+	return err
+}
+
+// holdUntilClosed keeps the in-flight slot taken by a request until its response body is closed.
+func holdUntilClosed(resp *http.Response, release func()) {
+	if resp.Body == nil {
+		release()
+		return
+	}
+	resp.Body = &releaseOnClose{ReadCloser: resp.Body, release: release}
+}
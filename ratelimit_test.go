@@ -0,0 +1,110 @@
+package sarvam
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRateLimiterTokenBucket(t *testing.T) {
+	limiter := newRateLimiter(RateLimit{RequestsPerSecond: 20, Burst: 2})
+
+	start := time.Now()
+	for range 4 {
+		release, err := limiter.acquire(context.Background())
+		require.NoError(t, err)
+		release()
+	}
+	// Two requests use the burst, the next two wait 50ms each.
+	assert.GreaterOrEqual(t, time.Since(start), 90*time.Millisecond)
+}
+
+func TestRateLimiterFailFast(t *testing.T) {
+	limiter := newRateLimiter(RateLimit{RequestsPerSecond: 1, FailFast: true})
+
+	release, err := limiter.acquire(context.Background())
+	require.NoError(t, err)
+	release()
+
+	_, err = limiter.acquire(context.Background())
+	assert.ErrorIs(t, err, ErrThrottled)
+}
+
+func TestRateLimiterMaxInFlight(t *testing.T) {
+	limiter := newRateLimiter(RateLimit{MaxInFlight: 1, FailFast: true})
+
+	release, err := limiter.acquire(context.Background())
+	require.NoError(t, err)
+
+	_, err = limiter.acquire(context.Background())
+	assert.ErrorIs(t, err, ErrThrottled)
+
+	release()
+	release() // releasing twice must not free a second slot
+	release, err = limiter.acquire(context.Background())
+	require.NoError(t, err)
+	release()
+}
+
+func TestRateLimiterWaitCanceled(t *testing.T) {
+	limiter := newRateLimiter(RateLimit{MaxInFlight: 1})
+	release, err := limiter.acquire(context.Background())
+	require.NoError(t, err)
+	defer release()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	_, err = limiter.acquire(ctx)
+	assert.ErrorIs(t, err, ErrRequestCanceled)
+}
+
+func TestClientMaxInFlightPerEndpoint(t *testing.T) {
+	var current, peak atomic.Int32
+	httpTestServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := current.Add(1)
+		defer current.Add(-1)
+		for {
+			p := peak.Load()
+			if n <= p || peak.CompareAndSwap(p, n) {
+				break
+			}
+		}
+		time.Sleep(20 * time.Millisecond)
+		w.Write([]byte(`{"request_id":"1","language_code":"en-IN","script_code":"Latn"}`))
+	}))
+	defer httpTestServer.Close()
+
+	client := NewClient("test",
+		WithBaseURL(httpTestServer.URL),
+		WithRateLimit(EndpointTextLID, RateLimit{MaxInFlight: 2}),
+	)
+	assert.Nil(t, client.rateLimiterFor(httpTestServer.URL+EndpointTranslate))
+
+	var wg sync.WaitGroup
+	for range 6 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := client.IdentifyLanguage("hello")
+			assert.NoError(t, err)
+		}()
+	}
+	wg.Wait()
+	assert.LessOrEqual(t, peak.Load(), int32(2))
+}
+
+func TestClientDefaultRateLimit(t *testing.T) {
+	client := NewClient("test",
+		WithDefaultRateLimit(RateLimit{RequestsPerSecond: 1}),
+		WithRateLimit(EndpointTranslate, RateLimit{RequestsPerSecond: 5}),
+	)
+	assert.Same(t, client.defaultRateLimiter, client.rateLimiterFor(client.baseURL+EndpointSpeechToText))
+	assert.Same(t, client.rateLimiters[EndpointTranslate], client.rateLimiterFor(client.baseURL+EndpointTranslate))
+}
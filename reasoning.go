@@ -0,0 +1,131 @@
+package sarvam
+
+import "strings"
+
+const (
+	thinkOpenTag  = "<think>"
+	thinkCloseTag = "</think>"
+)
+
+// splitReasoning separates the <think>...</think> reasoning block that sarvam-m puts at
+// the start of its replies from the answer that follows it. A block that is never closed,
+// for example because the reply was cut off, is all reasoning.
+//
+// Replies to requests that ask for reasoning sometimes omit the opening tag. If untagged is
+// set, everything before a closing tag in such a reply is reasoning; otherwise a reply that
+// does not open with a <think> tag is all answer, even if it mentions a closing tag.
+func splitReasoning(content string, untagged bool) (reasoning, answer string) {
+	trimmed := strings.TrimLeft(content, " \t\r\n")
+	if strings.HasPrefix(trimmed, thinkOpenTag) {
+		trimmed = trimmed[len(thinkOpenTag):]
+		reasoning, answer, _ = strings.Cut(trimmed, thinkCloseTag)
+	} else if before, after, found := strings.Cut(trimmed, thinkCloseTag); untagged && found && !strings.Contains(before, thinkOpenTag) {
+		reasoning, answer = before, after
+	} else {
+		return "", content
+	}
+	return strings.TrimSpace(reasoning), strings.TrimLeft(answer, " \t\r\n")
+}
+
+// reasoningState is the position of a reasoningSplitter within a reply.
+type reasoningState int
+
+const (
+	reasoningStateStart reasoningState = iota
+	reasoningStateThinking
+	reasoningStateUntagged
+	reasoningStateAnswerStart
+	reasoningStateAnswer
+)
+
+// reasoningSplitter separates reasoning from the answer in a reply that arrives in pieces,
+// holding back text that may turn out to be part of a tag.
+//
+// If holdUntagged is set, a reply that does not open with a <think> tag is held back until
+// a closing tag shows that it started with reasoning, or until it ends, so that it is split
+// the same way as by splitReasoning with untagged set. Otherwise such a reply is passed
+// through as it arrives.
+type reasoningSplitter struct {
+	holdUntagged bool
+	state        reasoningState
+	pending      string
+}
+
+// write consumes the next piece of the reply and returns the reasoning and answer text
+// that can be emitted so far.
+func (s *reasoningSplitter) write(text string) (reasoning, answer string) {
+	s.pending += text
+	for {
+		switch s.state {
+		case reasoningStateStart:
+			trimmed := strings.TrimLeft(s.pending, " \t\r\n")
+			switch {
+			case trimmed == "" || strings.HasPrefix(thinkOpenTag, trimmed):
+				return reasoning, answer
+			case strings.HasPrefix(trimmed, thinkOpenTag):
+				s.pending = strings.TrimLeft(trimmed[len(thinkOpenTag):], " \t\r\n")
+				s.state = reasoningStateThinking
+			case s.holdUntagged:
+				s.state = reasoningStateUntagged
+			default:
+				s.state = reasoningStateAnswer
+			}
+		case reasoningStateUntagged:
+			before, after, found := strings.Cut(s.pending, thinkCloseTag)
+			if strings.Contains(before, thinkOpenTag) {
+				s.state = reasoningStateAnswer
+				continue
+			}
+			if !found {
+				return reasoning, answer
+			}
+			reasoning += strings.TrimLeft(before, " \t\r\n")
+			s.pending = after
+			s.state = reasoningStateAnswerStart
+		case reasoningStateThinking:
+			if before, after, found := strings.Cut(s.pending, thinkCloseTag); found {
+				reasoning += before
+				s.pending = after
+				s.state = reasoningStateAnswerStart
+				continue
+			}
+			keep := partialSuffix(s.pending, thinkCloseTag)
+			reasoning += s.pending[:len(s.pending)-keep]
+			s.pending = s.pending[len(s.pending)-keep:]
+			return reasoning, answer
+		case reasoningStateAnswerStart:
+			s.pending = strings.TrimLeft(s.pending, " \t\r\n")
+			if s.pending == "" {
+				return reasoning, answer
+			}
+			s.state = reasoningStateAnswer
+		case reasoningStateAnswer:
+			answer += s.pending
+			s.pending = ""
+			return reasoning, answer
+		}
+	}
+}
+
+// flush returns any text held back once the reply is complete.
+func (s *reasoningSplitter) flush() (reasoning, answer string) {
+	pending := s.pending
+	s.pending = ""
+	if s.state == reasoningStateThinking {
+		return pending, ""
+	}
+	if s.state == reasoningStateStart && strings.TrimSpace(pending) == "" {
+		return "", ""
+	}
+	return "", pending
+}
+
+// partialSuffix returns the length of the longest suffix of s that is a proper prefix of tag.
+func partialSuffix(s, tag string) int {
+	for n := min(len(tag)-1, len(s)); n > 0; n-- {
+		if strings.HasSuffix(s, tag[:n]) {
+			return n
+		}
+	}
+	return 0
+}
@@ -0,0 +1,233 @@
+package sarvam
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSplitReasoning(t *testing.T) {
+	tests := []struct {
+		content   string
+		reasoning string
+		answer    string
+	}{
+		{"Hello!", "", "Hello!"},
+		{"<think>\nThe user greets me.\n</think>\n\nHello!", "The user greets me.", "Hello!"},
+		{"\n<think>Thinking</think>Hi", "Thinking", "Hi"},
+		{"Reasoning without an opening tag</think>\nHi", "Reasoning without an opening tag", "Hi"},
+		{"<think>Cut off mid-thou", "Cut off mid-thou", ""},
+		{"Use <think> tags like <think>this</think>", "", "Use <think> tags like <think>this</think>"},
+		{"Thinking</think>Mention </think> and <think>", "Thinking", "Mention </think> and <think>"},
+	}
+	for _, test := range tests {
+		reasoning, answer := splitReasoning(test.content, true)
+		assert.Equal(t, test.reasoning, reasoning, test.content)
+		assert.Equal(t, test.answer, answer, test.content)
+	}
+
+	// Without reasoning requested, only blocks opened by a tag are reasoning.
+	reasoning, answer := splitReasoning("Use the </think> tag to close reasoning.", false)
+	assert.Empty(t, reasoning)
+	assert.Equal(t, "Use the </think> tag to close reasoning.", answer)
+	reasoning, answer = splitReasoning("<think>Thinking</think>Hi", false)
+	assert.Equal(t, "Thinking", reasoning)
+	assert.Equal(t, "Hi", answer)
+}
+
+func TestReasoningSplitterChunkBoundaries(t *testing.T) {
+	content := "\n<think>\nThe user greets me.\n</think>\n\nHello, <b>friend</b>!"
+
+	// Split the content into two pieces at every possible position.
+	for i := range len(content) + 1 {
+		var splitter reasoningSplitter
+		var reasoning, answer string
+		for _, piece := range []string{content[:i], content[i:]} {
+			r, a := splitter.write(piece)
+			reasoning += r
+			answer += a
+		}
+		r, a := splitter.flush()
+		reasoning += r
+		answer += a
+
+		assert.Equal(t, "The user greets me.\n", reasoning, "split at %d", i)
+		assert.Equal(t, "Hello, <b>friend</b>!", answer, "split at %d", i)
+	}
+}
+
+func TestReasoningSplitterMatchesSplitReasoning(t *testing.T) {
+	contents := []string{
+		"Hello!",
+		"  Hello!",
+		"\n<think>\nThe user greets me.\n</think>\n\nHello!",
+		"Reasoning without an opening tag</think>\nHi",
+		"\nReasoning without an opening tag\n</think>\n\n",
+		"Use <think> tags like <think>this</think>",
+		"<think>Cut off mid-thou",
+	}
+	for _, untagged := range []bool{true, false} {
+		for _, content := range contents {
+			testReasoningSplitterMatchesSplitReasoning(t, content, untagged)
+		}
+	}
+}
+
+func testReasoningSplitterMatchesSplitReasoning(t *testing.T, content string, untagged bool) {
+	t.Helper()
+	want := ChatCompletionChoice{Message: Message{Content: content}}
+	want.Reasoning, want.Message.Content = splitReasoning(content, untagged)
+
+	// Split the content into two pieces at every possible position.
+	for i := range len(content) + 1 {
+		splitter := reasoningSplitter{holdUntagged: untagged}
+		var reasoning, answer string
+		for _, piece := range []string{content[:i], content[i:]} {
+			r, a := splitter.write(piece)
+			reasoning += r
+			answer += a
+		}
+		r, a := splitter.flush()
+		reasoning += r
+		answer += a
+
+		assert.Equal(t, want.Reasoning, strings.TrimSpace(reasoning), "%q split at %d, untagged %t", content, i, untagged)
+		assert.Equal(t, want.Message.Content, answer, "%q split at %d, untagged %t", content, i, untagged)
+	}
+}
+
+// newReplyServer returns a server answering every chat completion with content, streamed
+// in three pieces if streaming was requested.
+func newReplyServer(t *testing.T, content string) *httptest.Server {
+	t.Helper()
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var request struct {
+			Stream bool `json:"stream"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&request))
+		if !request.Stream {
+			data, err := json.Marshal(content)
+			require.NoError(t, err)
+			fmt.Fprintf(w, `{"id":"c1","choices":[{"index":0,"message":{"role":"assistant","content":%s}}]}`, data)
+			return
+		}
+		third := len(content) / 3
+		for _, piece := range []string{content[:third], content[third : 2*third], content[2*third:]} {
+			piece, err := json.Marshal(piece)
+			require.NoError(t, err)
+			fmt.Fprintf(w, "data: {\"id\":\"c1\",\"choices\":[{\"index\":0,\"delta\":{\"content\":%s}}]}\n\n", piece)
+		}
+		fmt.Fprint(w, "data: [DONE]\n\n")
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+func TestChatCompletionReasoningWithoutOpeningTag(t *testing.T) {
+	tests := []struct {
+		name      string
+		content   string
+		params    *ChatCompletionParams
+		reasoning string
+		answer    string
+	}{
+		{
+			"reasoning requested", "The user greets me.</think>\n\nHello!",
+			&ChatCompletionParams{ReasoningEffort: Ptr(ReasoningEffortLow)}, "The user greets me.", "Hello!",
+		},
+		{
+			"reasoning not requested", "Use the </think> tag to close reasoning.",
+			nil, "", "Use the </think> tag to close reasoning.",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := NewClient("test", WithBaseURL(newReplyServer(t, tt.content).URL))
+			response, err := client.ChatCompletion([]Message{NewUserMessage("Hi")}, ChatCompletionModelSarvamM, tt.params)
+			require.NoError(t, err)
+			assert.Equal(t, tt.reasoning, response.GetFirstChoiceReasoning())
+			assert.Equal(t, tt.answer, response.GetFirstChoiceContent())
+
+			stream, err := client.ChatCompletionStream([]Message{NewUserMessage("Hi")}, ChatCompletionModelSarvamM, tt.params)
+			require.NoError(t, err)
+			defer stream.Close()
+			streamed, err := stream.Collect()
+			require.NoError(t, err)
+			assert.Equal(t, tt.reasoning, streamed.GetFirstChoiceReasoning())
+			assert.Equal(t, tt.answer, streamed.GetFirstChoiceContent())
+		})
+	}
+}
+
+func TestReasoningSplitterWithoutReasoning(t *testing.T) {
+	var splitter reasoningSplitter
+	_, answer := splitter.write("<")
+	assert.Empty(t, answer)
+	_, answer = splitter.write("b>bold</b>")
+	assert.Equal(t, "<b>bold</b>", answer)
+}
+
+func TestChatCompletionChoiceReasoning(t *testing.T) {
+	var response ChatCompletionResponse
+	err := json.Unmarshal([]byte(`{"choices":[{"index":0,"message":{"role":"assistant","content":"<think>Short answer wanted.</think>\n\n42"}}]}`), &response)
+	require.NoError(t, err)
+	assert.Equal(t, "42", response.GetFirstChoiceContent())
+	assert.Equal(t, "Short answer wanted.", response.GetFirstChoiceReasoning())
+
+	// Round-tripping the response keeps the reasoning.
+	data, err := json.Marshal(response)
+	require.NoError(t, err)
+	var decoded ChatCompletionResponse
+	require.NoError(t, json.Unmarshal(data, &decoded))
+	assert.Equal(t, response, decoded)
+}
+
+func TestChatCompletionStreamReasoning(t *testing.T) {
+	httpTestServer := newStreamServer(t,
+		`data: {"id":"c1","choices":[{"index":0,"delta":{"role":"assistant","content":"<thi"}}]}`,
+		`data: {"id":"c1","choices":[{"index":0,"delta":{"content":"nk>Greeting. </th"}}]}`,
+		`data: {"id":"c1","choices":[{"index":0,"delta":{"content":"ink>\n\nHel"}}]}`,
+		`data: {"id":"c1","choices":[{"index":0,"delta":{"content":"lo"},"finish_reason":"stop"}]}`,
+		`data: [DONE]`,
+	)
+
+	client := NewClient("test", WithBaseURL(httpTestServer.URL))
+	stream, err := client.ChatCompletionStream([]Message{NewUserMessage("Hi")}, ChatCompletionModelSarvamM, &ChatCompletionParams{ReasoningEffort: Ptr(ReasoningEffortLow)})
+	require.NoError(t, err)
+	defer stream.Close()
+
+	var contents []string
+	var acc ChatCompletionAccumulator
+	for chunk, err := range stream.All() {
+		require.NoError(t, err)
+		contents = append(contents, chunk.Choices[0].Delta.Content)
+		require.NoError(t, acc.Add(chunk))
+	}
+	assert.Equal(t, []string{"", "", "Hel", "lo"}, contents)
+
+	response := acc.Response()
+	assert.Equal(t, "Greeting.", response.GetFirstChoiceReasoning())
+	assert.Equal(t, "Hello", response.GetFirstChoiceContent())
+}
+
+func TestChatCompletionStreamFlushesAtEOF(t *testing.T) {
+	httpTestServer := newStreamServer(t,
+		`data: {"id":"c1","choices":[{"index":0,"delta":{"content":"<think>Still thinking</thi"}}]}`,
+	)
+
+	client := NewClient("test", WithBaseURL(httpTestServer.URL))
+	stream, err := client.ChatCompletionStream([]Message{NewUserMessage("Hi")}, ChatCompletionModelSarvamM, nil)
+	require.NoError(t, err)
+	defer stream.Close()
+
+	response, err := stream.Collect()
+	require.NoError(t, err)
+	assert.Equal(t, "Still thinking</thi", response.GetFirstChoiceReasoning())
+	assert.Empty(t, response.GetFirstChoiceContent())
+}
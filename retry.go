@@ -0,0 +1,153 @@
+package sarvam
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// RetryPolicy controls how requests that fail with transient errors are retried.
+// The zero value disables retries.
+type RetryPolicy struct {
+	// MaxAttempts is the total number of attempts, including the first one.
+	// Values below 2 disable retries.
+	MaxAttempts int
+	// InitialBackoff is the delay before the first retry.
+	InitialBackoff time.Duration
+	// MaxBackoff caps the delay between attempts. It does not apply to delays
+	// requested by the server through the Retry-After header.
+	MaxBackoff time.Duration
+	// Multiplier is the factor by which the delay grows after each attempt.
+	// Values below 1 are treated as 1.
+	Multiplier float64
+	// Jitter randomizes each delay by up to this fraction of its value, between 0 and 1.
+	Jitter float64
+	// RetryableStatus reports whether a response with the given status code should be
+	// retried. If nil, DefaultRetryableStatus is used.
+	RetryableStatus func(statusCode int) bool
+}
+
+// DefaultRetryPolicy returns a policy that makes up to three attempts with exponential
+// backoff starting at 500ms.
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts:    3,
+		InitialBackoff: 500 * time.Millisecond,
+		MaxBackoff:     10 * time.Second,
+		Multiplier:     2,
+		Jitter:         0.2,
+	}
+}
+
+// DefaultRetryableStatus reports whether statusCode indicates a transient failure:
+// 408, 429, 500, 502, 503 or 504.
+func DefaultRetryableStatus(statusCode int) bool {
+	switch statusCode {
+	case http.StatusRequestTimeout,
+		http.StatusTooManyRequests,
+		http.StatusInternalServerError,
+		http.StatusBadGateway,
+		http.StatusServiceUnavailable,
+		http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+// WithRetryPolicy sets the policy used to retry failed requests.
+func WithRetryPolicy(policy RetryPolicy) Option {
+	return func(c *Client) {
+		c.retryPolicy = policy
+	}
+}
+
+// errBuildRequest marks failures to prepare a request, which sending it again cannot fix.
+var errBuildRequest = errors.New("failed to create request")
+
+// shouldRetry reports whether an attempt that produced resp and err should be retried.
+// Errors are retried unless the request was canceled, throttled by a FailFast rate limit
+// or could not be built.
+func (p RetryPolicy) shouldRetry(resp *http.Response, err error) bool {
+	if err != nil {
+		return !errors.Is(err, ErrRequestCanceled) && !errors.Is(err, ErrThrottled) && !errors.Is(err, errBuildRequest)
+	}
+	if !p.retryableStatus(resp.StatusCode) {
+		return false
+	}
+	// Exhausted quota is reported as 429 too, but waiting does not help.
+	return resp.StatusCode != http.StatusTooManyRequests || !quotaExceededResponse(resp)
+}
+
+// retryableStatus reports whether a response with statusCode should be retried, using
+// DefaultRetryableStatus if the policy does not set RetryableStatus.
+func (p RetryPolicy) retryableStatus(statusCode int) bool {
+	if p.RetryableStatus == nil {
+		return DefaultRetryableStatus(statusCode)
+	}
+	return p.RetryableStatus(statusCode)
+}
+
+// backoff returns the delay before the attempt following the given one.
+// A Retry-After header on resp takes precedence over the computed delay.
+func (p RetryPolicy) backoff(attempt int, resp *http.Response) time.Duration {
+	if resp != nil {
+		if delay, ok := parseRetryAfter(resp.Header.Get("Retry-After"), time.Now()); ok {
+			return delay
+		}
+	}
+
+	multiplier := max(p.Multiplier, 1)
+	delay := float64(p.InitialBackoff) * math.Pow(multiplier, float64(attempt-1))
+	if p.MaxBackoff > 0 {
+		delay = min(delay, float64(p.MaxBackoff))
+	}
+	if p.Jitter > 0 {
+		jitter := min(p.Jitter, 1)
+		delay += delay * jitter * (2*rand.Float64() - 1)
+	}
+	return time.Duration(delay)
+}
+
+// parseRetryAfter parses a Retry-After header value given either in seconds or as an HTTP date.
+func parseRetryAfter(value string, now time.Time) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			return 0, false
+		}
+		return time.Duration(seconds) * time.Second, true
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		return max(date.Sub(now), 0), true
+	}
+	return 0, false
+}
+
+// discardBody drains and closes the body of a response that will not be returned to the caller,
+// allowing the underlying connection to be reused.
+func discardBody(resp *http.Response) {
+	if resp == nil || resp.Body == nil {
+		return
+	}
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+	resp.Body.Close()
+}
+
+// sleep waits for d or until ctx is done, whichever happens first.
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,170 @@
+package sarvam
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRetryOnTransientStatus(t *testing.T) {
+	var attempts atomic.Int32
+	httpTestServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if attempts.Add(1) < 3 {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.Write([]byte(`{"request_id":"1","language_code":"hi-IN","script_code":"Deva"}`))
+	}))
+	defer httpTestServer.Close()
+
+	client := NewClient("test", WithBaseURL(httpTestServer.URL), WithRetryPolicy(RetryPolicy{MaxAttempts: 3}))
+	response, err := client.IdentifyLanguage("नमस्ते")
+	require.NoError(t, err)
+	assert.Equal(t, LanguageHindi, response.Language)
+	assert.Equal(t, int32(3), attempts.Load())
+}
+
+func TestRetryGivesUpAfterMaxAttempts(t *testing.T) {
+	var attempts atomic.Int32
+	httpTestServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts.Add(1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer httpTestServer.Close()
+
+	client := NewClient("test", WithBaseURL(httpTestServer.URL), WithRetryPolicy(RetryPolicy{MaxAttempts: 2, InitialBackoff: time.Millisecond}))
+	_, err := client.IdentifyLanguage("hello")
+	var httpErr *HTTPError
+	require.ErrorAs(t, err, &httpErr)
+	assert.Equal(t, http.StatusServiceUnavailable, httpErr.StatusCode)
+	assert.Equal(t, int32(2), attempts.Load())
+}
+
+func TestRetryDoesNotRetryClientErrors(t *testing.T) {
+	var attempts atomic.Int32
+	httpTestServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts.Add(1)
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer httpTestServer.Close()
+
+	client := NewClient("test", WithBaseURL(httpTestServer.URL), WithRetryPolicy(DefaultRetryPolicy()))
+	_, err := client.IdentifyLanguage("hello")
+	assert.Error(t, err)
+	assert.Equal(t, int32(1), attempts.Load())
+}
+
+func TestRetryDoesNotRetryThrottledRequests(t *testing.T) {
+	var attempts atomic.Int32
+	httpTestServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts.Add(1)
+		w.Write([]byte(`{"request_id":"1","language_code":"hi-IN","script_code":"Deva"}`))
+	}))
+	defer httpTestServer.Close()
+
+	client := NewClient("test",
+		WithBaseURL(httpTestServer.URL),
+		WithRetryPolicy(DefaultRetryPolicy()),
+		WithRateLimit(EndpointTextLID, RateLimit{RequestsPerSecond: 0.1, FailFast: true}),
+	)
+	_, err := client.IdentifyLanguage("hello")
+	require.NoError(t, err)
+
+	start := time.Now()
+	_, err = client.IdentifyLanguage("hello")
+	assert.ErrorIs(t, err, ErrThrottled)
+	assert.Less(t, time.Since(start), 100*time.Millisecond, "FailFast must not wait for retries")
+	assert.Equal(t, int32(1), attempts.Load())
+}
+
+func TestRetryDoesNotRetryInvalidRequests(t *testing.T) {
+	client := NewClient("test", WithBaseURL("http://[::1"), WithRetryPolicy(DefaultRetryPolicy()))
+	start := time.Now()
+	_, err := client.IdentifyLanguage("hello")
+	assert.ErrorContains(t, err, "failed to create request")
+	assert.Less(t, time.Since(start), 100*time.Millisecond)
+}
+
+func TestRetryReplaysMultipartBody(t *testing.T) {
+	var bodies []string
+	httpTestServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		bodies = append(bodies, string(body))
+		if len(bodies) == 1 {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		w.Write([]byte(`{"request_id":"1","transcript":"hello","language_code":"en-IN"}`))
+	}))
+	defer httpTestServer.Close()
+
+	client := NewClient("test", WithBaseURL(httpTestServer.URL), WithRetryPolicy(RetryPolicy{MaxAttempts: 2}))
+	response, err := client.SpeechToText(strings.NewReader("RIFF\x00\x00\x00\x00WAVE-audio"), SpeechToTextParams{})
+	require.NoError(t, err)
+	assert.Equal(t, "hello", response.Transcript)
+	require.Len(t, bodies, 2)
+	assert.Contains(t, bodies[0], "WAVE-audio")
+	assert.Equal(t, bodies[0], bodies[1])
+}
+
+func TestRetryStopsWhenContextDone(t *testing.T) {
+	httpTestServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Retry-After", "60")
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer httpTestServer.Close()
+
+	client := NewClient("test", WithBaseURL(httpTestServer.URL), WithRetryPolicy(DefaultRetryPolicy()))
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	_, err := client.IdentifyLanguageWithContext(ctx, "hello")
+	assert.ErrorIs(t, err, ErrRequestCanceled)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func TestRetryPolicyBackoff(t *testing.T) {
+	policy := RetryPolicy{InitialBackoff: 100 * time.Millisecond, MaxBackoff: time.Second, Multiplier: 2}
+	assert.Equal(t, 100*time.Millisecond, policy.backoff(1, nil))
+	assert.Equal(t, 200*time.Millisecond, policy.backoff(2, nil))
+	assert.Equal(t, 400*time.Millisecond, policy.backoff(3, nil))
+	assert.Equal(t, time.Second, policy.backoff(10, nil))
+
+	policy.Jitter = 0.5
+	for range 100 {
+		delay := policy.backoff(1, nil)
+		assert.GreaterOrEqual(t, delay, 50*time.Millisecond)
+		assert.LessOrEqual(t, delay, 150*time.Millisecond)
+	}
+
+	resp := &http.Response{Header: http.Header{"Retry-After": []string{"7"}}}
+	assert.Equal(t, 7*time.Second, policy.backoff(1, resp))
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2025, 8, 1, 12, 0, 0, 0, time.UTC)
+
+	delay, ok := parseRetryAfter("3", now)
+	assert.True(t, ok)
+	assert.Equal(t, 3*time.Second, delay)
+
+	delay, ok = parseRetryAfter(now.Add(90*time.Second).Format(http.TimeFormat), now)
+	assert.True(t, ok)
+	assert.Equal(t, 90*time.Second, delay)
+
+	_, ok = parseRetryAfter("", now)
+	assert.False(t, ok)
+	_, ok = parseRetryAfter("soon", now)
+	assert.False(t, ok)
+	_, ok = parseRetryAfter("-1", now)
+	assert.False(t, ok)
+}
@@ -0,0 +1,393 @@
+package sarvamtest
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"unicode/utf8"
+
+	"code.abhai.dev/sarvam"
+)
+
+// RecordEnv is the environment variable that makes NewCassette record real interactions
+// instead of replaying them, when set to a non-empty value.
+const RecordEnv = "SARVAM_RECORD"
+
+// redactedHeaders are replaced by a placeholder in recorded requests.
+var redactedHeaders = []string{"Api-Subscription-Key", "Authorization"}
+
+const redacted = "[REDACTED]"
+
+// Mode selects whether a Cassette records or replays interactions.
+type Mode int
+
+const (
+	// ModeReplay answers requests from the recorded interactions, without network access.
+	ModeReplay Mode = iota
+	// ModeRecord sends requests to the real API and records the interactions.
+	ModeRecord
+)
+
+// MissingRecordingError is returned by a replaying Cassette for a request that was not recorded.
+type MissingRecordingError struct {
+	Cassette string // Path of the cassette file
+	Method   string
+	Path     string
+	Body     string // Normalized request body
+}
+
+func (e *MissingRecordingError) Error() string {
+	body := e.Body
+	if len(body) > 200 {
+		body = body[:200] + "..."
+	}
+	return fmt.Sprintf("sarvamtest: no recording of %s %s with body %s in %s; set %s=1 to record it",
+		e.Method, e.Path, body, e.Cassette, RecordEnv)
+}
+
+// Cassette is an http.RoundTripper that records HTTP interactions to a file and replays
+// them later, so that tests exercising the real API can run without network access or an
+// API key. It is safe for concurrent use.
+//
+// Requests are matched on method, URL path and body. JSON bodies are compared
+// irrespective of formatting and key order, and multipart forms irrespective of their
+// boundary and part order, with uploaded files compared by hash. Identical requests are
+// answered in the order they were recorded, and the last answer is repeated once they
+// run out. The api-subscription-key and Authorization headers are never written to the file.
+type Cassette struct {
+	path      string
+	mode      Mode
+	transport http.RoundTripper
+
+	mu           sync.Mutex
+	interactions []interaction
+	used         []bool
+}
+
+// cassetteFile is the stored form of a cassette.
+type cassetteFile struct {
+	Interactions []interaction `json:"interactions"`
+}
+
+type interaction struct {
+	Request  recordedRequest  `json:"request"`
+	Response recordedResponse `json:"response"`
+}
+
+type recordedRequest struct {
+	Method string      `json:"method"`
+	Path   string      `json:"path"`
+	Header http.Header `json:"header,omitempty"`
+	Body   string      `json:"body"`
+}
+
+type recordedResponse struct {
+	Status       int         `json:"status"`
+	Header       http.Header `json:"header,omitempty"`
+	Body         string      `json:"body"`
+	BodyEncoding string      `json:"body_encoding,omitempty"` // "base64" for binary bodies
+}
+
+// LoadCassette creates a cassette stored at path. In ModeReplay the file must exist; in
+// ModeRecord requests are sent through transport, or http.DefaultTransport if nil, and the
+// file is written by Save.
+func LoadCassette(path string, mode Mode, transport http.RoundTripper) (*Cassette, error) {
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+	c := &Cassette{path: path, mode: mode, transport: transport}
+	if mode == ModeRecord {
+		return c, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read cassette: %w", err)
+	}
+	var file cassetteFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("failed to parse cassette %s: %w", path, err)
+	}
+	c.interactions = file.Interactions
+	c.used = make([]bool, len(file.Interactions))
+	return c, nil
+}
+
+// NewCassette creates a cassette stored at path for the duration of a test. It records
+// when the RecordEnv environment variable is set and replays otherwise, failing the test
+// if the file cannot be loaded. Recordings are saved when the test finishes.
+func NewCassette(tb testing.TB, path string) *Cassette {
+	tb.Helper()
+	mode := ModeReplay
+	if os.Getenv(RecordEnv) != "" {
+		mode = ModeRecord
+	}
+	c, err := LoadCassette(path, mode, nil)
+	if err != nil {
+		tb.Fatalf("sarvamtest: %v; set %s=1 to record it", err, RecordEnv)
+	}
+	tb.Cleanup(func() {
+		if err := c.Save(); err != nil {
+			tb.Errorf("sarvamtest: %v", err)
+		}
+	})
+	return c
+}
+
+// Mode returns whether the cassette records or replays.
+func (c *Cassette) Mode() Mode {
+	return c.mode
+}
+
+// Client returns a client sending its requests through the cassette. When recording, it
+// uses the API key in the SARVAM_API_KEY environment variable.
+func (c *Cassette) Client(opts ...sarvam.Option) *sarvam.Client {
+	apiKey := "replay"
+	if c.mode == ModeRecord {
+		apiKey = os.Getenv("SARVAM_API_KEY")
+	}
+	return sarvam.NewClient(apiKey, append([]sarvam.Option{sarvam.WithTransport(c)}, opts...)...)
+}
+
+// Save writes the recorded interactions to the cassette file. It does nothing when replaying.
+func (c *Cassette) Save() error {
+	if c.mode != ModeRecord {
+		return nil
+	}
+	c.mu.Lock()
+	data, err := json.MarshalIndent(cassetteFile{Interactions: c.interactions}, "", "  ")
+	c.mu.Unlock()
+	if err != nil {
+		return fmt.Errorf("failed to encode cassette: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(c.path), 0o755); err != nil {
+		return fmt.Errorf("failed to create cassette directory: %w", err)
+	}
+	if err := os.WriteFile(c.path, append(data, '\n'), 0o644); err != nil {
+		return fmt.Errorf("failed to write cassette: %w", err)
+	}
+	return nil
+}
+
+// RoundTrip implements http.RoundTripper.
+func (c *Cassette) RoundTrip(req *http.Request) (*http.Response, error) {
+	var body []byte
+	if req.Body != nil {
+		var err error
+		body, err = io.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read request body: %w", err)
+		}
+	}
+	recorded := recordedRequest{
+		Method: req.Method,
+		Path:   req.URL.Path,
+		Header: redactHeader(req.Header),
+		Body:   normalizeBody(req.Header.Get("Content-Type"), body),
+	}
+
+	if c.mode == ModeRecord {
+		return c.record(req, body, recorded)
+	}
+	return c.replay(req, recorded)
+}
+
+// record sends req and adds the interaction. The response body is captured as the caller
+// reads it, so streamed responses still arrive incrementally.
+func (c *Cassette) record(req *http.Request, body []byte, recorded recordedRequest) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	req.Body = io.NopCloser(bytes.NewReader(body))
+	resp, err := c.transport.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	i := len(c.interactions)
+	c.interactions = append(c.interactions, interaction{
+		Request:  recorded,
+		Response: recordedResponse{Status: resp.StatusCode, Header: resp.Header.Clone()},
+	})
+	c.mu.Unlock()
+
+	resp.Body = &recordingBody{ReadCloser: resp.Body, done: func(data []byte) {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		c.interactions[i].Response.setBody(data)
+	}}
+	return resp, nil
+}
+
+// replay answers req from the first unused matching interaction, or the last matching one.
+func (c *Cassette) replay(req *http.Request, recorded recordedRequest) (*http.Response, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	match := -1
+	for i, in := range c.interactions {
+		if in.Request.Method != recorded.Method || in.Request.Path != recorded.Path || in.Request.Body != recorded.Body {
+			continue
+		}
+		match = i
+		if !c.used[i] {
+			break
+		}
+	}
+	if match < 0 {
+		return nil, &MissingRecordingError{Cassette: c.path, Method: recorded.Method, Path: recorded.Path, Body: recorded.Body}
+	}
+	c.used[match] = true
+
+	recordedResp := c.interactions[match].Response
+	body, err := recordedResp.body()
+	if err != nil {
+		return nil, fmt.Errorf("sarvamtest: invalid recording of %s %s: %w", recorded.Method, recorded.Path, err)
+	}
+	header := recordedResp.Header.Clone()
+	if header == nil {
+		header = make(http.Header)
+	}
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", recordedResp.Status, http.StatusText(recordedResp.Status)),
+		StatusCode:    recordedResp.Status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}, nil
+}
+
+func (r *recordedResponse) setBody(data []byte) {
+	if utf8.Valid(data) {
+		r.Body, r.BodyEncoding = string(data), ""
+		return
+	}
+	r.Body, r.BodyEncoding = base64.StdEncoding.EncodeToString(data), "base64"
+}
+
+func (r recordedResponse) body() ([]byte, error) {
+	switch r.BodyEncoding {
+	case "":
+		return []byte(r.Body), nil
+	case "base64":
+		return base64.StdEncoding.DecodeString(r.Body)
+	default:
+		return nil, fmt.Errorf("unknown body encoding %q", r.BodyEncoding)
+	}
+}
+
+// recordingBody passes a response body through, handing everything read to done once the
+// body is exhausted or closed.
+type recordingBody struct {
+	io.ReadCloser
+	buf  bytes.Buffer
+	once sync.Once
+	done func(data []byte)
+}
+
+func (b *recordingBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	b.buf.Write(p[:n])
+	if errors.Is(err, io.EOF) {
+		b.once.Do(func() { b.done(b.buf.Bytes()) })
+	}
+	return n, err
+}
+
+func (b *recordingBody) Close() error {
+	b.once.Do(func() { b.done(b.buf.Bytes()) })
+	return b.ReadCloser.Close()
+}
+
+// redactHeader copies header, masking credentials.
+func redactHeader(header http.Header) http.Header {
+	header = header.Clone()
+	for _, key := range redactedHeaders {
+		if header.Get(key) != "" {
+			header.Set(key, redacted)
+		}
+	}
+	return header
+}
+
+// normalizeBody returns a form of a request body that does not depend on JSON formatting
+// or on how a multipart form was encoded.
+func normalizeBody(contentType string, body []byte) string {
+	if len(body) == 0 {
+		return ""
+	}
+	mediaType, params, _ := mime.ParseMediaType(contentType)
+	switch {
+	case mediaType == "multipart/form-data":
+		if normalized, err := normalizeMultipart(body, params["boundary"]); err == nil {
+			return normalized
+		}
+	case mediaType == "application/json" || strings.HasSuffix(mediaType, "+json"):
+		var v any
+		decoder := json.NewDecoder(bytes.NewReader(body))
+		decoder.UseNumber()
+		if err := decoder.Decode(&v); err == nil {
+			// Maps are encoded with sorted keys.
+			if normalized, err := json.Marshal(v); err == nil {
+				return string(normalized)
+			}
+		}
+	}
+	if utf8.Valid(body) {
+		return string(body)
+	}
+	return "sha256:" + hashHex(body)
+}
+
+// normalizeMultipart encodes the fields of a multipart form as JSON with sorted keys,
+// replacing files by their name, size and hash. Repeated fields keep their order.
+func normalizeMultipart(body []byte, boundary string) (string, error) {
+	form := make(map[string][]any)
+	reader := multipart.NewReader(bytes.NewReader(body), boundary)
+	for {
+		part, err := reader.NextPart()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return "", err
+		}
+		data, err := io.ReadAll(part)
+		if err != nil {
+			return "", err
+		}
+		if part.FileName() != "" {
+			form[part.FormName()] = append(form[part.FormName()], map[string]any{
+				"filename": part.FileName(),
+				"size":     len(data),
+				"sha256":   hashHex(data),
+			})
+		} else {
+			form[part.FormName()] = append(form[part.FormName()], string(data))
+		}
+	}
+	normalized, err := json.Marshal(form)
+	return string(normalized), err
+}
+
+func hashHex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,141 @@
+package sarvamtest_test
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"code.abhai.dev/sarvam"
+	"code.abhai.dev/sarvam/sarvamtest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// offlineURL is never reached by a replaying cassette.
+const offlineURL = "http://sarvam.invalid"
+
+// exercise makes one request to each kind of endpoint and returns what it got back.
+func exercise(t *testing.T, client *sarvam.Client) []string {
+	t.Helper()
+	var results []string
+
+	translation, err := client.Translate("hello", sarvam.LanguageEnglish, sarvam.LanguageHindi, nil)
+	require.NoError(t, err)
+	results = append(results, translation.TranslatedText)
+
+	transcript, err := client.SpeechToText(strings.NewReader(testWAV), sarvam.SpeechToTextParams{Language: sarvam.Ptr(sarvam.LanguageHindi)})
+	require.NoError(t, err)
+	results = append(results, transcript.Transcript)
+
+	stream, err := client.ChatCompletionStream([]sarvam.Message{sarvam.NewUserMessage("tell me a story")}, sarvam.ChatCompletionModelSarvamM, nil)
+	require.NoError(t, err)
+	for chunk, err := range stream.All() {
+		require.NoError(t, err)
+		results = append(results, chunk.Choices[0].Delta.Content)
+	}
+
+	speech, err := client.TextToSpeechStream("hello", sarvam.LanguageHindi, sarvam.TextToSpeechParams{})
+	require.NoError(t, err)
+	audio, err := io.ReadAll(speech)
+	require.NoError(t, speech.Close())
+	require.NoError(t, err)
+	results = append(results, string(audio))
+
+	return results
+}
+
+func TestCassetteRecordAndReplay(t *testing.T) {
+	server := sarvamtest.NewServer(t)
+	path := filepath.Join(t.TempDir(), "cassettes", "session.json")
+
+	recorder, err := sarvamtest.LoadCassette(path, sarvamtest.ModeRecord, nil)
+	require.NoError(t, err)
+	recorded := exercise(t, sarvam.NewClient(sarvamtest.DefaultAPIKey, sarvam.WithBaseURL(server.URL), sarvam.WithTransport(recorder)))
+	require.NoError(t, recorder.Save())
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), sarvamtest.DefaultAPIKey)
+	assert.Contains(t, string(data), "[REDACTED]")
+	assert.Contains(t, string(data), "data: [DONE]")
+
+	player, err := sarvamtest.LoadCassette(path, sarvamtest.ModeReplay, nil)
+	require.NoError(t, err)
+	replayed := exercise(t, player.Client(sarvam.WithBaseURL(offlineURL)))
+	assert.Equal(t, recorded, replayed)
+	assert.Len(t, server.Requests(), 4, "replaying must not reach the server")
+}
+
+func TestCassetteReplaysRepeatedRequestsInOrder(t *testing.T) {
+	server := sarvamtest.NewServer(t)
+	server.FailNext(sarvam.EndpointTextLID, 1, http.StatusServiceUnavailable, "internal_server_error", "unavailable")
+	path := filepath.Join(t.TempDir(), "retry.json")
+	policy := sarvam.WithRetryPolicy(sarvam.RetryPolicy{MaxAttempts: 2, InitialBackoff: time.Millisecond})
+
+	recorder, err := sarvamtest.LoadCassette(path, sarvamtest.ModeRecord, nil)
+	require.NoError(t, err)
+	client := sarvam.NewClient(sarvamtest.DefaultAPIKey, sarvam.WithBaseURL(server.URL), sarvam.WithTransport(recorder), policy)
+	_, err = client.IdentifyLanguage("hello")
+	require.NoError(t, err)
+	require.NoError(t, recorder.Save())
+
+	player, err := sarvamtest.LoadCassette(path, sarvamtest.ModeReplay, nil)
+	require.NoError(t, err)
+
+	_, err = player.Client(sarvam.WithBaseURL(offlineURL)).IdentifyLanguage("hello")
+	assert.ErrorIs(t, err, sarvam.ErrServer, "first replay without retries gets the recorded failure")
+
+	client = player.Client(sarvam.WithBaseURL(offlineURL))
+	resp, err := client.IdentifyLanguage("hello")
+	require.NoError(t, err, "exhausted recordings repeat the last answer")
+	assert.Equal(t, sarvam.LanguageEnglish, resp.Language)
+}
+
+func TestCassetteMatchesNormalizedJSON(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "json.json")
+	require.NoError(t, os.WriteFile(path, []byte(`{"interactions": [{
+		"request": {"method": "POST", "path": "/translate", "body": "{\"a\":1,\"b\":[\"x\"]}"},
+		"response": {"status": 200, "header": {"Content-Type": ["application/json"]}, "body": "{\"ok\":true}"}
+	}]}`), 0o644))
+	player, err := sarvamtest.LoadCassette(path, sarvamtest.ModeReplay, nil)
+	require.NoError(t, err)
+
+	req, err := http.NewRequest(http.MethodPost, offlineURL+"/translate", strings.NewReader("{\n  \"b\": [\"x\"],\n  \"a\": 1\n}"))
+	require.NoError(t, err)
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := player.RoundTrip(req)
+	require.NoError(t, err)
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.JSONEq(t, `{"ok":true}`, string(body))
+}
+
+func TestCassetteMissingRecording(t *testing.T) {
+	server := sarvamtest.NewServer(t)
+	path := filepath.Join(t.TempDir(), "missing.json")
+
+	recorder, err := sarvamtest.LoadCassette(path, sarvamtest.ModeRecord, nil)
+	require.NoError(t, err)
+	_, err = sarvam.NewClient(sarvamtest.DefaultAPIKey, sarvam.WithBaseURL(server.URL), sarvam.WithTransport(recorder)).IdentifyLanguage("hello")
+	require.NoError(t, err)
+	require.NoError(t, recorder.Save())
+
+	player, err := sarvamtest.LoadCassette(path, sarvamtest.ModeReplay, nil)
+	require.NoError(t, err)
+	_, err = player.Client(sarvam.WithBaseURL(offlineURL)).IdentifyLanguage("goodbye")
+	var missing *sarvamtest.MissingRecordingError
+	require.True(t, errors.As(err, &missing), "got %v", err)
+	assert.Equal(t, http.MethodPost, missing.Method)
+	assert.Equal(t, sarvam.EndpointTextLID, missing.Path)
+	assert.Contains(t, err.Error(), path)
+	assert.Contains(t, err.Error(), "goodbye")
+
+	_, err = sarvamtest.LoadCassette(filepath.Join(t.TempDir(), "absent.json"), sarvamtest.ModeReplay, nil)
+	assert.ErrorIs(t, err, os.ErrNotExist)
+}
@@ -0,0 +1,407 @@
+package sarvamtest
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"sync"
+
+	"code.abhai.dev/sarvam"
+)
+
+// The fakes below implement the service interfaces of the sarvam package in memory, for
+// tests of code that depends on those interfaces rather than on *sarvam.Client. The zero
+// value of each fake is ready to use and answers like the default handlers of Server; set
+// its Func fields to script other answers or errors. Fakes record every call, fail with
+// sarvam.ErrRequestCanceled once the context is done, and are safe for concurrent use.
+
+var (
+	_ sarvam.Translator         = (*FakeTranslator)(nil)
+	_ sarvam.Transliterator     = (*FakeTransliterator)(nil)
+	_ sarvam.LanguageIdentifier = (*FakeLanguageIdentifier)(nil)
+	_ sarvam.Synthesizer        = (*FakeSynthesizer)(nil)
+	_ sarvam.Transcriber        = (*FakeTranscriber)(nil)
+	_ sarvam.ChatCompleter      = (*FakeChatCompleter)(nil)
+)
+
+// callLog records the calls made to a fake.
+type callLog[T any] struct {
+	mu    sync.Mutex
+	calls []T
+}
+
+func (l *callLog[T]) add(call T) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.calls = append(l.calls, call)
+}
+
+func (l *callLog[T]) all() []T {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return append([]T(nil), l.calls...)
+}
+
+// canceled returns the error of a done context, wrapped like the errors of sarvam.Client.
+func canceled(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return fmt.Errorf("%w: %w", sarvam.ErrRequestCanceled, err)
+	}
+	return nil
+}
+
+// TranslateCall is a call to FakeTranslator.
+type TranslateCall struct {
+	Input          string
+	SourceLanguage sarvam.Language
+	TargetLanguage sarvam.Language
+	Params         *sarvam.TranslateParams
+}
+
+// FakeTranslator is an in-memory sarvam.Translator.
+type FakeTranslator struct {
+	// Func translates. If nil, the input is tagged with the target language, as in "[hi-IN] hello".
+	Func func(ctx context.Context, call TranslateCall) (*sarvam.TranslationResponse, error)
+
+	calls callLog[TranslateCall]
+}
+
+// Translate implements sarvam.Translator.
+func (f *FakeTranslator) Translate(input string, sourceLanguageCode, targetLanguageCode sarvam.Language, params *sarvam.TranslateParams) (*sarvam.TranslationResponse, error) {
+	return f.TranslateWithContext(context.Background(), input, sourceLanguageCode, targetLanguageCode, params)
+}
+
+// TranslateWithContext implements sarvam.Translator.
+func (f *FakeTranslator) TranslateWithContext(ctx context.Context, input string, sourceLanguageCode, targetLanguageCode sarvam.Language, params *sarvam.TranslateParams) (*sarvam.TranslationResponse, error) {
+	call := TranslateCall{Input: input, SourceLanguage: sourceLanguageCode, TargetLanguage: targetLanguageCode, Params: params}
+	f.calls.add(call)
+	if err := canceled(ctx); err != nil {
+		return nil, err
+	}
+	if f.Func != nil {
+		return f.Func(ctx, call)
+	}
+
+	source := sourceLanguageCode
+	if source == sarvam.LanguageAuto {
+		source = sarvam.LanguageEnglish
+	}
+	return &sarvam.TranslationResponse{
+		RequestId:      "sarvamtest-translate",
+		TranslatedText: fmt.Sprintf("[%s] %s", string(targetLanguageCode), input),
+		SourceLanguage: source,
+	}, nil
+}
+
+// Calls returns the calls made so far, in order.
+func (f *FakeTranslator) Calls() []TranslateCall {
+	return f.calls.all()
+}
+
+// TransliterateCall is a call to FakeTransliterator.
+type TransliterateCall struct {
+	Input          string
+	SourceLanguage sarvam.Language
+	TargetLanguage sarvam.Language
+	Params         *sarvam.TransliterateParams
+}
+
+// FakeTransliterator is an in-memory sarvam.Transliterator.
+type FakeTransliterator struct {
+	// Func transliterates. If nil, the input is returned unchanged.
+	Func func(ctx context.Context, call TransliterateCall) (*sarvam.TransliterationResponse, error)
+
+	calls callLog[TransliterateCall]
+}
+
+// Transliterate implements sarvam.Transliterator.
+func (f *FakeTransliterator) Transliterate(input string, sourceLanguage sarvam.Language, targetLanguage sarvam.Language, params *sarvam.TransliterateParams) (*sarvam.TransliterationResponse, error) {
+	return f.TransliterateWithContext(context.Background(), input, sourceLanguage, targetLanguage, params)
+}
+
+// TransliterateWithContext implements sarvam.Transliterator.
+func (f *FakeTransliterator) TransliterateWithContext(ctx context.Context, input string, sourceLanguage sarvam.Language, targetLanguage sarvam.Language, params *sarvam.TransliterateParams) (*sarvam.TransliterationResponse, error) {
+	call := TransliterateCall{Input: input, SourceLanguage: sourceLanguage, TargetLanguage: targetLanguage, Params: params}
+	f.calls.add(call)
+	if err := canceled(ctx); err != nil {
+		return nil, err
+	}
+	if f.Func != nil {
+		return f.Func(ctx, call)
+	}
+
+	source := sourceLanguage
+	if source == sarvam.LanguageAuto {
+		source = sarvam.LanguageEnglish
+	}
+	return &sarvam.TransliterationResponse{
+		RequestId:          "sarvamtest-transliterate",
+		TransliteratedText: input,
+		SourceLanguage:     source,
+	}, nil
+}
+
+// Calls returns the calls made so far, in order.
+func (f *FakeTransliterator) Calls() []TransliterateCall {
+	return f.calls.all()
+}
+
+// FakeLanguageIdentifier is an in-memory sarvam.LanguageIdentifier.
+type FakeLanguageIdentifier struct {
+	// Func identifies the language of its input. If nil, every input is English in Latin script.
+	Func func(ctx context.Context, input string) (*sarvam.LanguageIdentificationResponse, error)
+
+	calls callLog[string]
+}
+
+// IdentifyLanguage implements sarvam.LanguageIdentifier.
+func (f *FakeLanguageIdentifier) IdentifyLanguage(input string) (*sarvam.LanguageIdentificationResponse, error) {
+	return f.IdentifyLanguageWithContext(context.Background(), input)
+}
+
+// IdentifyLanguageWithContext implements sarvam.LanguageIdentifier.
+func (f *FakeLanguageIdentifier) IdentifyLanguageWithContext(ctx context.Context, input string) (*sarvam.LanguageIdentificationResponse, error) {
+	f.calls.add(input)
+	if err := canceled(ctx); err != nil {
+		return nil, err
+	}
+	if f.Func != nil {
+		return f.Func(ctx, input)
+	}
+	return &sarvam.LanguageIdentificationResponse{
+		RequestId: "sarvamtest-text-lid",
+		Language:  sarvam.LanguageEnglish,
+		Script:    sarvam.ScriptLatin,
+	}, nil
+}
+
+// Calls returns the inputs of the calls made so far, in order.
+func (f *FakeLanguageIdentifier) Calls() []string {
+	return f.calls.all()
+}
+
+// TextToSpeechCall is a call to FakeSynthesizer.
+type TextToSpeechCall struct {
+	Text           string
+	TargetLanguage sarvam.Language
+	Params         sarvam.TextToSpeechParams
+	Stream         bool // Whether the call was to TextToSpeechStream
+}
+
+// FakeSynthesizer is an in-memory sarvam.Synthesizer.
+type FakeSynthesizer struct {
+	// Func synthesizes speech, returning a WAV file. If nil, it returns 10ms of silence per
+	// character of text.
+	Func func(ctx context.Context, call TextToSpeechCall) ([]byte, error)
+
+	calls callLog[TextToSpeechCall]
+}
+
+// TextToSpeech implements sarvam.Synthesizer.
+func (f *FakeSynthesizer) TextToSpeech(text string, targetLanguage sarvam.Language, params sarvam.TextToSpeechParams) (*sarvam.TextToSpeechResponse, error) {
+	return f.TextToSpeechWithContext(context.Background(), text, targetLanguage, params)
+}
+
+// TextToSpeechWithContext implements sarvam.Synthesizer.
+func (f *FakeSynthesizer) TextToSpeechWithContext(ctx context.Context, text string, targetLanguage sarvam.Language, params sarvam.TextToSpeechParams) (*sarvam.TextToSpeechResponse, error) {
+	speech, err := f.synthesize(ctx, TextToSpeechCall{Text: text, TargetLanguage: targetLanguage, Params: params})
+	if err != nil {
+		return nil, err
+	}
+	return &sarvam.TextToSpeechResponse{
+		RequestId: "sarvamtest-tts",
+		Audios:    []string{base64.StdEncoding.EncodeToString(speech)},
+	}, nil
+}
+
+// TextToSpeechStream implements sarvam.Synthesizer.
+func (f *FakeSynthesizer) TextToSpeechStream(text string, targetLanguage sarvam.Language, params sarvam.TextToSpeechParams) (io.ReadCloser, error) {
+	return f.TextToSpeechStreamWithContext(context.Background(), text, targetLanguage, params)
+}
+
+// TextToSpeechStreamWithContext implements sarvam.Synthesizer.
+func (f *FakeSynthesizer) TextToSpeechStreamWithContext(ctx context.Context, text string, targetLanguage sarvam.Language, params sarvam.TextToSpeechParams) (io.ReadCloser, error) {
+	speech, err := f.synthesize(ctx, TextToSpeechCall{Text: text, TargetLanguage: targetLanguage, Params: params, Stream: true})
+	if err != nil {
+		return nil, err
+	}
+	return io.NopCloser(bytes.NewReader(speech)), nil
+}
+
+func (f *FakeSynthesizer) synthesize(ctx context.Context, call TextToSpeechCall) ([]byte, error) {
+	f.calls.add(call)
+	if err := canceled(ctx); err != nil {
+		return nil, err
+	}
+	if f.Func != nil {
+		return f.Func(ctx, call)
+	}
+	var sampleRate sarvam.SpeechSampleRate
+	if call.Params.SpeechSampleRate != nil {
+		sampleRate = *call.Params.SpeechSampleRate
+	}
+	return silentSpeech(call.Text, sampleRate).Bytes(), nil
+}
+
+// Calls returns the calls made so far, in order.
+func (f *FakeSynthesizer) Calls() []TextToSpeechCall {
+	return f.calls.all()
+}
+
+// SpeechToTextCall is a call to FakeTranscriber.SpeechToText.
+type SpeechToTextCall struct {
+	Audio  []byte
+	Params sarvam.SpeechToTextParams
+}
+
+// SpeechToTextTranslateCall is a call to FakeTranscriber.SpeechToTextTranslate.
+type SpeechToTextTranslateCall struct {
+	Audio  []byte
+	Params sarvam.SpeechToTextTranslateParams
+}
+
+// FakeTranscriber is an in-memory sarvam.Transcriber. The audio of each call is read in full.
+type FakeTranscriber struct {
+	// SpeechToTextFunc transcribes. If nil, the transcript reports the size of the audio.
+	SpeechToTextFunc func(ctx context.Context, call SpeechToTextCall) (*sarvam.SpeechToTextResponse, error)
+	// SpeechToTextTranslateFunc transcribes and translates. If nil, the transcript reports
+	// the size of the audio.
+	SpeechToTextTranslateFunc func(ctx context.Context, call SpeechToTextTranslateCall) (*sarvam.SpeechToTextTranslateResponse, error)
+
+	speechToTextCalls          callLog[SpeechToTextCall]
+	speechToTextTranslateCalls callLog[SpeechToTextTranslateCall]
+}
+
+// SpeechToText implements sarvam.Transcriber.
+func (f *FakeTranscriber) SpeechToText(speech io.Reader, params sarvam.SpeechToTextParams) (*sarvam.SpeechToTextResponse, error) {
+	return f.SpeechToTextWithContext(context.Background(), speech, params)
+}
+
+// SpeechToTextWithContext implements sarvam.Transcriber.
+func (f *FakeTranscriber) SpeechToTextWithContext(ctx context.Context, speech io.Reader, params sarvam.SpeechToTextParams) (*sarvam.SpeechToTextResponse, error) {
+	data, err := io.ReadAll(speech)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read audio: %w", err)
+	}
+	call := SpeechToTextCall{Audio: data, Params: params}
+	f.speechToTextCalls.add(call)
+	if err := canceled(ctx); err != nil {
+		return nil, err
+	}
+	if f.SpeechToTextFunc != nil {
+		return f.SpeechToTextFunc(ctx, call)
+	}
+
+	language := sarvam.LanguageHindi
+	if params.Language != nil {
+		language = *params.Language
+	}
+	return &sarvam.SpeechToTextResponse{
+		RequestId:  "sarvamtest-stt",
+		Transcript: fmt.Sprintf("transcript of %d bytes", len(data)),
+		Language:   language,
+	}, nil
+}
+
+// SpeechToTextTranslate implements sarvam.Transcriber.
+func (f *FakeTranscriber) SpeechToTextTranslate(speech io.Reader, params sarvam.SpeechToTextTranslateParams) (*sarvam.SpeechToTextTranslateResponse, error) {
+	return f.SpeechToTextTranslateWithContext(context.Background(), speech, params)
+}
+
+// SpeechToTextTranslateWithContext implements sarvam.Transcriber.
+func (f *FakeTranscriber) SpeechToTextTranslateWithContext(ctx context.Context, speech io.Reader, params sarvam.SpeechToTextTranslateParams) (*sarvam.SpeechToTextTranslateResponse, error) {
+	data, err := io.ReadAll(speech)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read audio: %w", err)
+	}
+	call := SpeechToTextTranslateCall{Audio: data, Params: params}
+	f.speechToTextTranslateCalls.add(call)
+	if err := canceled(ctx); err != nil {
+		return nil, err
+	}
+	if f.SpeechToTextTranslateFunc != nil {
+		return f.SpeechToTextTranslateFunc(ctx, call)
+	}
+	return &sarvam.SpeechToTextTranslateResponse{
+		RequestId:  "sarvamtest-stt-translate",
+		Transcript: fmt.Sprintf("transcript of %d bytes", len(data)),
+		Language:   sarvam.LanguageHindi,
+	}, nil
+}
+
+// SpeechToTextCalls returns the calls to SpeechToText made so far, in order.
+func (f *FakeTranscriber) SpeechToTextCalls() []SpeechToTextCall {
+	return f.speechToTextCalls.all()
+}
+
+// SpeechToTextTranslateCalls returns the calls to SpeechToTextTranslate made so far, in order.
+func (f *FakeTranscriber) SpeechToTextTranslateCalls() []SpeechToTextTranslateCall {
+	return f.speechToTextTranslateCalls.all()
+}
+
+// ChatCompletionCall is a call to FakeChatCompleter.
+type ChatCompletionCall struct {
+	Messages []sarvam.Message
+	Model    sarvam.ChatCompletionModel
+	Params   *sarvam.ChatCompletionParams
+	Stream   bool // Whether the call was to ChatCompletionStream
+}
+
+// FakeChatCompleter is an in-memory sarvam.ChatCompleter. Streamed completions are the
+// completion Func returns, split into one chunk per word.
+type FakeChatCompleter struct {
+	// Func completes the conversation. If nil, it answers with the last message, prefixed by "echo: ".
+	Func func(ctx context.Context, call ChatCompletionCall) (*sarvam.ChatCompletionResponse, error)
+
+	calls callLog[ChatCompletionCall]
+}
+
+// ChatCompletion implements sarvam.ChatCompleter.
+func (f *FakeChatCompleter) ChatCompletion(messages []sarvam.Message, model sarvam.ChatCompletionModel, req *sarvam.ChatCompletionParams) (*sarvam.ChatCompletionResponse, error) {
+	return f.ChatCompletionWithContext(context.Background(), messages, model, req)
+}
+
+// ChatCompletionWithContext implements sarvam.ChatCompleter.
+func (f *FakeChatCompleter) ChatCompletionWithContext(ctx context.Context, messages []sarvam.Message, model sarvam.ChatCompletionModel, req *sarvam.ChatCompletionParams) (*sarvam.ChatCompletionResponse, error) {
+	return f.complete(ctx, ChatCompletionCall{Messages: messages, Model: model, Params: req})
+}
+
+// ChatCompletionStream implements sarvam.ChatCompleter.
+func (f *FakeChatCompleter) ChatCompletionStream(messages []sarvam.Message, model sarvam.ChatCompletionModel, req *sarvam.ChatCompletionParams) (*sarvam.ChatCompletionStream, error) {
+	return f.ChatCompletionStreamWithContext(context.Background(), messages, model, req)
+}
+
+// ChatCompletionStreamWithContext implements sarvam.ChatCompleter.
+func (f *FakeChatCompleter) ChatCompletionStreamWithContext(ctx context.Context, messages []sarvam.Message, model sarvam.ChatCompletionModel, req *sarvam.ChatCompletionParams) (*sarvam.ChatCompletionStream, error) {
+	resp, err := f.complete(ctx, ChatCompletionCall{Messages: messages, Model: model, Params: req, Stream: true})
+	if err != nil {
+		return nil, err
+	}
+
+	chunks := completionChunks(resp)
+	events := make([]any, len(chunks))
+	for i, chunk := range chunks {
+		events[i] = chunk
+	}
+	var body bytes.Buffer
+	writeEvents(&body, events)
+	return sarvam.NewChatCompletionStream(ctx, io.NopCloser(&body)), nil
+}
+
+func (f *FakeChatCompleter) complete(ctx context.Context, call ChatCompletionCall) (*sarvam.ChatCompletionResponse, error) {
+	f.calls.add(call)
+	if err := canceled(ctx); err != nil {
+		return nil, err
+	}
+	if f.Func != nil {
+		return f.Func(ctx, call)
+	}
+	return echoCompletion(call.Messages, call.Model), nil
+}
+
+// Calls returns the calls made so far, in order.
+func (f *FakeChatCompleter) Calls() []ChatCompletionCall {
+	return f.calls.all()
+}
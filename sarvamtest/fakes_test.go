@@ -0,0 +1,131 @@
+package sarvamtest_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"code.abhai.dev/sarvam"
+	"code.abhai.dev/sarvam/audio"
+	"code.abhai.dev/sarvam/sarvamtest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// greet is application code depending only on the interfaces it needs.
+func greet(ctx context.Context, translator sarvam.Translator, synthesizer sarvam.Synthesizer, language sarvam.Language) ([]byte, error) {
+	translation, err := translator.TranslateWithContext(ctx, "hello", sarvam.LanguageEnglish, language, nil)
+	if err != nil {
+		return nil, err
+	}
+	speech, err := synthesizer.TextToSpeechWithContext(ctx, translation.TranslatedText, language, sarvam.TextToSpeechParams{})
+	if err != nil {
+		return nil, err
+	}
+	return speech.Bytes()
+}
+
+func TestFakesAsDependencies(t *testing.T) {
+	translator := &sarvamtest.FakeTranslator{}
+	synthesizer := &sarvamtest.FakeSynthesizer{}
+
+	speech, err := greet(context.Background(), translator, synthesizer, sarvam.LanguageHindi)
+	require.NoError(t, err)
+	_, err = audio.ParseWAV(speech)
+	require.NoError(t, err)
+
+	require.Len(t, translator.Calls(), 1)
+	assert.Equal(t, sarvam.LanguageHindi, translator.Calls()[0].TargetLanguage)
+	require.Len(t, synthesizer.Calls(), 1)
+	assert.Equal(t, "[hi-IN] hello", synthesizer.Calls()[0].Text)
+
+	failure := errors.New("translation failed")
+	translator.Func = func(ctx context.Context, call sarvamtest.TranslateCall) (*sarvam.TranslationResponse, error) {
+		return nil, failure
+	}
+	_, err = greet(context.Background(), translator, synthesizer, sarvam.LanguageTamil)
+	assert.ErrorIs(t, err, failure)
+	assert.Len(t, synthesizer.Calls(), 1)
+}
+
+func TestFakeDefaults(t *testing.T) {
+	transliteration, err := (&sarvamtest.FakeTransliterator{}).Transliterate("namaste", sarvam.LanguageEnglish, sarvam.LanguageHindi, nil)
+	require.NoError(t, err)
+	assert.Equal(t, "namaste", transliteration.TransliteratedText)
+
+	identifier := &sarvamtest.FakeLanguageIdentifier{}
+	identified, err := identifier.IdentifyLanguage("hello")
+	require.NoError(t, err)
+	assert.Equal(t, sarvam.LanguageEnglish, identified.Language)
+	assert.Equal(t, []string{"hello"}, identifier.Calls())
+
+	synthesizer := &sarvamtest.FakeSynthesizer{}
+	stream, err := synthesizer.TextToSpeechStream("hello", sarvam.LanguageHindi, sarvam.TextToSpeechParams{})
+	require.NoError(t, err)
+	data, err := io.ReadAll(stream)
+	require.NoError(t, err)
+	_, err = audio.ParseWAV(data)
+	require.NoError(t, err)
+	assert.True(t, synthesizer.Calls()[0].Stream)
+
+	transcriber := &sarvamtest.FakeTranscriber{}
+	transcript, err := transcriber.SpeechToText(strings.NewReader(testWAV), sarvam.SpeechToTextParams{Language: sarvam.Ptr(sarvam.LanguageTamil)})
+	require.NoError(t, err)
+	assert.Equal(t, "transcript of 16 bytes", transcript.Transcript)
+	assert.Equal(t, sarvam.LanguageTamil, transcript.Language)
+	assert.Equal(t, []byte(testWAV), transcriber.SpeechToTextCalls()[0].Audio)
+
+	translated, err := transcriber.SpeechToTextTranslate(strings.NewReader(testWAV), sarvam.SpeechToTextTranslateParams{})
+	require.NoError(t, err)
+	assert.NotEmpty(t, translated.Transcript)
+	assert.Len(t, transcriber.SpeechToTextTranslateCalls(), 1)
+}
+
+func TestFakeChatCompleter(t *testing.T) {
+	completer := &sarvamtest.FakeChatCompleter{}
+	messages := []sarvam.Message{sarvam.NewUserMessage("how are you")}
+
+	completion, err := completer.ChatCompletion(messages, sarvam.ChatCompletionModelSarvamM, nil)
+	require.NoError(t, err)
+	assert.Equal(t, "echo: how are you", completion.Choices[0].Message.Content)
+
+	completer.Func = func(ctx context.Context, call sarvamtest.ChatCompletionCall) (*sarvam.ChatCompletionResponse, error) {
+		return &sarvam.ChatCompletionResponse{Choices: []sarvam.ChatCompletionChoice{
+			{FinishReason: "stop", Message: sarvam.NewAssistantMessage("I am fine, thanks")},
+		}}, nil
+	}
+	stream, err := completer.ChatCompletionStream(messages, sarvam.ChatCompletionModelSarvamM, nil)
+	require.NoError(t, err)
+	defer stream.Close()
+	var chunks int
+	var content string
+	for chunk, err := range stream.All() {
+		require.NoError(t, err)
+		chunks++
+		content += chunk.Choices[0].Delta.Content
+	}
+	assert.Equal(t, "I am fine, thanks", content)
+	assert.Equal(t, 4, chunks)
+
+	calls := completer.Calls()
+	require.Len(t, calls, 2)
+	assert.False(t, calls[0].Stream)
+	assert.True(t, calls[1].Stream)
+	assert.Equal(t, sarvam.ChatCompletionModelSarvamM, calls[1].Model)
+}
+
+func TestFakesHonorCancellation(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := (&sarvamtest.FakeTranslator{}).TranslateWithContext(ctx, "hello", sarvam.LanguageEnglish, sarvam.LanguageHindi, nil)
+	assert.ErrorIs(t, err, sarvam.ErrRequestCanceled)
+	assert.ErrorIs(t, err, context.Canceled)
+
+	completer := &sarvamtest.FakeChatCompleter{}
+	_, err = completer.ChatCompletionStreamWithContext(ctx, []sarvam.Message{sarvam.NewUserMessage("hi")}, sarvam.ChatCompletionModelSarvamM, nil)
+	assert.ErrorIs(t, err, sarvam.ErrRequestCanceled)
+	assert.Len(t, completer.Calls(), 1)
+}
@@ -0,0 +1,534 @@
+// Package sarvamtest provides an in-process fake of the Sarvam AI API for testing code
+// that uses the sarvam package.
+//
+// A Server answers every endpoint with plausible canned results out of the box. Tests can
+// script responses per endpoint, inject errors and latency, and inspect the requests the
+// server received:
+//
+//	server := sarvamtest.NewServer(t)
+//	server.Enqueue(sarvam.EndpointTranslate, sarvamtest.Error(http.StatusTooManyRequests, "rate_limit_exceeded_error", "slow down"))
+//	client := server.Client()
+//	// ... exercise the code under test with client ...
+//	server.AssertCalled(t, sarvam.EndpointTranslate, 2)
+package sarvamtest
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"code.abhai.dev/sarvam"
+	"code.abhai.dev/sarvam/audio"
+)
+
+// DefaultAPIKey is the API key expected by a Server unless APIKey is changed.
+const DefaultAPIKey = "sarvamtest-key"
+
+// Request is a request received by a Server.
+type Request struct {
+	Method   string
+	Path     string
+	Query    url.Values
+	Header   http.Header
+	Body     []byte     // Raw request body
+	Form     url.Values // Fields of a multipart form, excluding the file
+	File     []byte     // Uploaded file of a multipart form
+	FileName string
+}
+
+// DecodeJSON decodes the JSON body of the request into v.
+func (r Request) DecodeJSON(v any) error {
+	return json.Unmarshal(r.Body, v)
+}
+
+// JSON returns the JSON body of the request decoded into a map, or nil if it is not a JSON object.
+func (r Request) JSON() map[string]any {
+	var m map[string]any
+	if err := r.DecodeJSON(&m); err != nil {
+		return nil
+	}
+	return m
+}
+
+// Response is a scripted response.
+type Response struct {
+	Status int           // Defaults to 200
+	Header http.Header   // Additional response headers
+	Body   any           // Written as is if []byte or string, and encoded as JSON otherwise
+	Events []any         // Server-sent events, each encoded as JSON, followed by [DONE]; used instead of Body
+	Delay  time.Duration // Time to wait before responding, in addition to the server's latency
+}
+
+// JSON returns a successful response with v as its JSON body.
+func JSON(v any) Response {
+	return Response{Body: v}
+}
+
+// Error returns an error response in the format of the API.
+func Error(status int, code, message string) Response {
+	return Response{
+		Status: status,
+		Body: map[string]any{
+			"error": map[string]string{"message": message, "code": code, "request_id": "sarvamtest-error"},
+		},
+	}
+}
+
+// ChatStream returns a streamed chat completion response made of chunks.
+func ChatStream(chunks ...sarvam.ChatCompletionChunk) Response {
+	events := make([]any, len(chunks))
+	for i, chunk := range chunks {
+		events[i] = chunk
+	}
+	return Response{Events: events}
+}
+
+// Handler computes the response to a request.
+type Handler func(req Request) Response
+
+// Server is a fake Sarvam AI API server. Its methods are safe for concurrent use.
+type Server struct {
+	*httptest.Server
+
+	mu       sync.Mutex
+	apiKey   string
+	latency  time.Duration
+	handlers map[string]Handler
+	queued   map[string][]Response
+	requests []Request
+}
+
+// NewServer starts a fake server, closed when the test finishes.
+func NewServer(tb testing.TB) *Server {
+	tb.Helper()
+	s := &Server{
+		apiKey:   DefaultAPIKey,
+		handlers: make(map[string]Handler),
+		queued:   make(map[string][]Response),
+	}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+	tb.Cleanup(s.Close)
+	return s
+}
+
+// Client returns a client configured to send requests to the server.
+func (s *Server) Client(opts ...sarvam.Option) *sarvam.Client {
+	s.mu.Lock()
+	apiKey := s.apiKey
+	s.mu.Unlock()
+	return sarvam.NewClient(apiKey, append([]sarvam.Option{sarvam.WithBaseURL(s.URL)}, opts...)...)
+}
+
+// SetAPIKey changes the API key requests must carry. An empty key accepts any request.
+func (s *Server) SetAPIKey(apiKey string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.apiKey = apiKey
+}
+
+// SetLatency delays every response by d.
+func (s *Server) SetLatency(d time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.latency = d
+}
+
+// Handle sets the handler for an endpoint, such as sarvam.EndpointTranslate, replacing
+// the default fake. Queued responses take precedence.
+func (s *Server) Handle(endpoint string, handler Handler) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.handlers[endpoint] = handler
+}
+
+// On makes the endpoint always answer with resp.
+func (s *Server) On(endpoint string, resp Response) {
+	s.Handle(endpoint, func(Request) Response { return resp })
+}
+
+// Enqueue adds responses the endpoint answers with, in order, before falling back to its handler.
+func (s *Server) Enqueue(endpoint string, resps ...Response) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.queued[endpoint] = append(s.queued[endpoint], resps...)
+}
+
+// FailNext makes the next n requests to the endpoint fail with an error response.
+func (s *Server) FailNext(endpoint string, n int, status int, code, message string) {
+	for range n {
+		s.Enqueue(endpoint, Error(status, code, message))
+	}
+}
+
+// Reset removes scripted responses and recorded requests.
+func (s *Server) Reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.handlers = make(map[string]Handler)
+	s.queued = make(map[string][]Response)
+	s.requests = nil
+}
+
+// Requests returns the requests received so far, in order.
+func (s *Server) Requests() []Request {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Request(nil), s.requests...)
+}
+
+// RequestsTo returns the requests received for an endpoint, in order.
+func (s *Server) RequestsTo(endpoint string) []Request {
+	var requests []Request
+	for _, req := range s.Requests() {
+		if req.Path == endpoint {
+			requests = append(requests, req)
+		}
+	}
+	return requests
+}
+
+// LastRequest returns the most recent request to an endpoint.
+func (s *Server) LastRequest(endpoint string) (Request, bool) {
+	requests := s.RequestsTo(endpoint)
+	if len(requests) == 0 {
+		return Request{}, false
+	}
+	return requests[len(requests)-1], true
+}
+
+// AssertCalled reports a test error unless the endpoint received exactly n requests.
+func (s *Server) AssertCalled(tb testing.TB, endpoint string, n int) bool {
+	tb.Helper()
+	if got := len(s.RequestsTo(endpoint)); got != n {
+		tb.Errorf("sarvamtest: %s received %d requests, want %d", endpoint, got, n)
+		return false
+	}
+	return true
+}
+
+// AssertNotCalled reports a test error if the endpoint received any request.
+func (s *Server) AssertNotCalled(tb testing.TB, endpoint string) bool {
+	tb.Helper()
+	return s.AssertCalled(tb, endpoint, 0)
+}
+
+// AssertRequest reports a test error unless the last request to the endpoint has a JSON
+// body or form field with the given value.
+func (s *Server) AssertRequest(tb testing.TB, endpoint, field string, want any) bool {
+	tb.Helper()
+	req, ok := s.LastRequest(endpoint)
+	if !ok {
+		tb.Errorf("sarvamtest: %s received no requests", endpoint)
+		return false
+	}
+
+	var got any
+	if body := req.JSON(); body != nil {
+		got = body[field]
+	} else if req.Form.Has(field) {
+		got = req.Form.Get(field)
+	}
+	// Compare through JSON so that, for example, typed strings match plain ones.
+	gotJSON, _ := json.Marshal(got)
+	wantJSON, _ := json.Marshal(want)
+	if !bytes.Equal(gotJSON, wantJSON) {
+		tb.Errorf("sarvamtest: %s field %q is %s, want %s", endpoint, field, gotJSON, wantJSON)
+		return false
+	}
+	return true
+}
+
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	req, err := readRequest(r)
+	if err != nil {
+		writeResponse(w, Error(http.StatusBadRequest, "invalid_request_error", err.Error()))
+		return
+	}
+
+	s.mu.Lock()
+	s.requests = append(s.requests, req)
+	latency := s.latency
+	var resp Response
+	// A request with the wrong key neither uses up a queued response nor reaches a handler.
+	if s.apiKey != "" && r.Header.Get("api-subscription-key") != s.apiKey {
+		resp = Error(http.StatusForbidden, "invalid_api_key_error", "Invalid API key")
+	} else if queued := s.queued[req.Path]; len(queued) > 0 {
+		resp, s.queued[req.Path] = queued[0], queued[1:]
+	} else if handler, ok := s.handlers[req.Path]; ok {
+		s.mu.Unlock()
+		resp = handler(req)
+		s.mu.Lock()
+	} else if handler, ok := defaultHandlers[req.Path]; ok {
+		resp = handler(req)
+	} else {
+		resp = Error(http.StatusNotFound, "not_found_error", "no such endpoint: "+req.Path)
+	}
+	s.mu.Unlock()
+
+	select {
+	case <-time.After(latency + resp.Delay):
+	case <-r.Context().Done():
+		return
+	}
+	writeResponse(w, resp)
+}
+
+// readRequest records the parts of r that tests inspect.
+func readRequest(r *http.Request) (Request, error) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		return Request{}, err
+	}
+	req := Request{
+		Method: r.Method,
+		Path:   r.URL.Path,
+		Query:  r.URL.Query(),
+		Header: r.Header.Clone(),
+		Body:   body,
+		Form:   url.Values{},
+	}
+
+	mediaType, params, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if mediaType == "multipart/form-data" {
+		reader := multipart.NewReader(bytes.NewReader(body), params["boundary"])
+		for {
+			part, err := reader.NextPart()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				return Request{}, fmt.Errorf("invalid multipart body: %w", err)
+			}
+			data, err := io.ReadAll(part)
+			if err != nil {
+				return Request{}, err
+			}
+			if part.FileName() != "" {
+				req.File, req.FileName = data, part.FileName()
+			} else {
+				req.Form.Add(part.FormName(), string(data))
+			}
+		}
+	}
+	return req, nil
+}
+
+// writeResponse writes resp, streaming its events if it has any.
+func writeResponse(w http.ResponseWriter, resp Response) {
+	for key, values := range resp.Header {
+		w.Header()[key] = values
+	}
+	status := resp.Status
+	if status == 0 {
+		status = http.StatusOK
+	}
+
+	if resp.Events != nil {
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.WriteHeader(status)
+		writeEvents(w, resp.Events)
+		return
+	}
+
+	var body []byte
+	switch b := resp.Body.(type) {
+	case nil:
+	case []byte:
+		body = b
+	case string:
+		body = []byte(b)
+	default:
+		body, _ = json.Marshal(b)
+		if w.Header().Get("Content-Type") == "" {
+			w.Header().Set("Content-Type", "application/json")
+		}
+	}
+	w.WriteHeader(status)
+	w.Write(body)
+}
+
+// writeEvents writes events as server-sent events terminated by [DONE], flushing each one.
+func writeEvents(w io.Writer, events []any) {
+	flusher, _ := w.(http.Flusher)
+	for _, event := range events {
+		data, _ := json.Marshal(event)
+		fmt.Fprintf(w, "data: %s\n\n", data)
+		if flusher != nil {
+			flusher.Flush()
+		}
+	}
+	io.WriteString(w, "data: [DONE]\n\n")
+}
+
+// defaultHandlers give every endpoint a plausible answer when nothing is scripted.
+var defaultHandlers = map[string]Handler{
+	sarvam.EndpointTranslate:             defaultTranslate,
+	sarvam.EndpointTextLID:               defaultIdentifyLanguage,
+	sarvam.EndpointTransliterate:         defaultTransliterate,
+	sarvam.EndpointTextToSpeech:          defaultTextToSpeech,
+	sarvam.EndpointTextToSpeechStream:    defaultTextToSpeechStream,
+	sarvam.EndpointSpeechToText:          defaultSpeechToText,
+	sarvam.EndpointSpeechToTextTranslate: defaultSpeechToText,
+	sarvam.EndpointChatCompletions:       defaultChatCompletion,
+}
+
+// defaultTranslate tags the input with the target language, as in "[hi-IN] hello".
+func defaultTranslate(req Request) Response {
+	body := req.JSON()
+	source, _ := body["source_language_code"].(string)
+	if source == "" || source == string(sarvam.LanguageAuto) {
+		source = string(sarvam.LanguageEnglish)
+	}
+	return JSON(map[string]any{
+		"request_id":           "sarvamtest-translate",
+		"translated_text":      fmt.Sprintf("[%v] %v", body["target_language_code"], body["input"]),
+		"source_language_code": source,
+	})
+}
+
+// defaultIdentifyLanguage identifies every input as English in Latin script.
+func defaultIdentifyLanguage(Request) Response {
+	return JSON(map[string]any{
+		"request_id":    "sarvamtest-text-lid",
+		"language_code": sarvam.LanguageEnglish,
+		"script_code":   sarvam.ScriptLatin,
+	})
+}
+
+// defaultTransliterate returns the input unchanged.
+func defaultTransliterate(req Request) Response {
+	body := req.JSON()
+	source, _ := body["source_language_code"].(string)
+	if source == "" || source == string(sarvam.LanguageAuto) {
+		source = string(sarvam.LanguageEnglish)
+	}
+	return JSON(map[string]any{
+		"request_id":           "sarvamtest-transliterate",
+		"transliterated_text":  body["input"],
+		"source_language_code": source,
+	})
+}
+
+// defaultTextToSpeech synthesizes silence, base64-encoded.
+func defaultTextToSpeech(req Request) Response {
+	return JSON(map[string]any{
+		"request_id": "sarvamtest-tts",
+		"audios":     []string{base64.StdEncoding.EncodeToString(requestSpeech(req).Bytes())},
+	})
+}
+
+// defaultTextToSpeechStream synthesizes silence as raw WAV.
+func defaultTextToSpeechStream(req Request) Response {
+	return Response{
+		Header: http.Header{"Content-Type": {"audio/wav"}},
+		Body:   requestSpeech(req).Bytes(),
+	}
+}
+
+// requestSpeech returns the silence answering a text-to-speech request.
+func requestSpeech(req Request) *audio.WAV {
+	body := req.JSON()
+	text, _ := body["text"].(string)
+	var sampleRate sarvam.SpeechSampleRate
+	if rate, ok := body["speech_sample_rate"].(float64); ok {
+		sampleRate = sarvam.SpeechSampleRate(rate)
+	}
+	return silentSpeech(text, sampleRate)
+}
+
+// silentSpeech returns 10ms of silence per character of text, at 22050Hz unless sampleRate is set.
+func silentSpeech(text string, sampleRate sarvam.SpeechSampleRate) *audio.WAV {
+	if sampleRate == 0 {
+		sampleRate = 22050
+	}
+	format := audio.Format{AudioFormat: audio.FormatPCM, Channels: 1, SampleRate: uint32(sampleRate), BitsPerSample: 16}
+	return audio.Silence(format, time.Duration(len([]rune(text)))*10*time.Millisecond)
+}
+
+// defaultSpeechToText reports the size of the uploaded audio.
+func defaultSpeechToText(req Request) Response {
+	language := req.Form.Get("language_code")
+	if language == "" || language == "unknown" {
+		language = string(sarvam.LanguageHindi)
+	}
+	return JSON(map[string]any{
+		"request_id":    "sarvamtest-stt",
+		"transcript":    fmt.Sprintf("transcript of %d bytes", len(req.File)),
+		"language_code": language,
+	})
+}
+
+// defaultChatCompletion echoes the last message, streaming it word by word if requested.
+func defaultChatCompletion(req Request) Response {
+	var body struct {
+		Model    sarvam.ChatCompletionModel `json:"model"`
+		Messages []sarvam.Message           `json:"messages"`
+		Stream   bool                       `json:"stream"`
+	}
+	if err := req.DecodeJSON(&body); err != nil || len(body.Messages) == 0 {
+		return Error(http.StatusBadRequest, "invalid_request_error", "messages are required")
+	}
+	resp := echoCompletion(body.Messages, body.Model)
+	if body.Stream {
+		return ChatStream(completionChunks(resp)...)
+	}
+	return JSON(resp)
+}
+
+// echoCompletion answers with the content of the last message.
+func echoCompletion(messages []sarvam.Message, model sarvam.ChatCompletionModel) *sarvam.ChatCompletionResponse {
+	var content string
+	if len(messages) > 0 {
+		content = "echo: " + messages[len(messages)-1].Content
+	}
+	return &sarvam.ChatCompletionResponse{
+		ID:     "sarvamtest-chat",
+		Model:  string(model),
+		Object: "chat.completion",
+		Choices: []sarvam.ChatCompletionChoice{{
+			FinishReason: "stop",
+			Message:      sarvam.NewAssistantMessage(content),
+		}},
+		Usage: &sarvam.Usage{PromptTokens: 1, CompletionTokens: 1, TotalTokens: 2},
+	}
+}
+
+// completionChunks splits the choices of resp into the chunks of a stream, one per word.
+func completionChunks(resp *sarvam.ChatCompletionResponse) []sarvam.ChatCompletionChunk {
+	var chunks []sarvam.ChatCompletionChunk
+	for _, choice := range resp.Choices {
+		words := strings.SplitAfter(choice.Message.Content, " ")
+		for i, word := range words {
+			delta := sarvam.ChatCompletionDelta{Content: word}
+			if i == 0 {
+				delta.Role = string(choice.Message.Role)
+			}
+			chunk := sarvam.ChatCompletionChunk{
+				ID:      resp.ID,
+				Created: resp.Created,
+				Model:   resp.Model,
+				Object:  "chat.completion.chunk",
+				Choices: []sarvam.ChatCompletionChunkChoice{{Index: choice.Index, Delta: delta}},
+			}
+			if i == len(words)-1 && choice.FinishReason != "" {
+				chunk.Choices[0].FinishReason = &choice.FinishReason
+			}
+			chunks = append(chunks, chunk)
+		}
+	}
+	if len(chunks) > 0 {
+		chunks[len(chunks)-1].Usage = resp.Usage
+	}
+	return chunks
+}
@@ -0,0 +1,230 @@
+package sarvamtest_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"code.abhai.dev/sarvam"
+	"code.abhai.dev/sarvam/audio"
+	"code.abhai.dev/sarvam/sarvamtest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testWAV = "RIFF\x24\x00\x00\x00WAVEfmt "
+
+func TestServerDefaults(t *testing.T) {
+	server := sarvamtest.NewServer(t)
+	client := server.Client()
+
+	translation, err := client.Translate("hello", sarvam.LanguageEnglish, sarvam.LanguageHindi, nil)
+	require.NoError(t, err)
+	assert.Equal(t, "[hi-IN] hello", translation.TranslatedText)
+	assert.Equal(t, sarvam.LanguageEnglish, translation.SourceLanguage)
+
+	identified, err := client.IdentifyLanguage("hello")
+	require.NoError(t, err)
+	assert.Equal(t, sarvam.LanguageEnglish, identified.Language)
+
+	transliteration, err := client.Transliterate("namaste", sarvam.LanguageEnglish, sarvam.LanguageHindi, nil)
+	require.NoError(t, err)
+	assert.Equal(t, "namaste", transliteration.TransliteratedText)
+
+	speech, err := client.TextToSpeech("hello", sarvam.LanguageHindi, sarvam.TextToSpeechParams{})
+	require.NoError(t, err)
+	wav, err := speech.WAV()
+	require.NoError(t, err)
+	assert.InDelta(t, 50*time.Millisecond, wav.Duration(), float64(time.Millisecond))
+
+	stream, err := client.TextToSpeechStream("hello", sarvam.LanguageHindi, sarvam.TextToSpeechParams{})
+	require.NoError(t, err)
+	data, err := io.ReadAll(stream)
+	require.NoError(t, stream.Close())
+	require.NoError(t, err)
+	_, err = audio.ParseWAV(data)
+	require.NoError(t, err)
+
+	transcript, err := client.SpeechToText(strings.NewReader(testWAV), sarvam.SpeechToTextParams{})
+	require.NoError(t, err)
+	assert.Equal(t, "transcript of 16 bytes", transcript.Transcript)
+
+	translated, err := client.SpeechToTextTranslate(strings.NewReader(testWAV), sarvam.SpeechToTextTranslateParams{})
+	require.NoError(t, err)
+	assert.NotEmpty(t, translated.Transcript)
+
+	messages := []sarvam.Message{sarvam.NewUserMessage("how are you")}
+	completion, err := client.ChatCompletion(messages, sarvam.ChatCompletionModelSarvamM, nil)
+	require.NoError(t, err)
+	assert.Equal(t, "echo: how are you", completion.Choices[0].Message.Content)
+
+	chat, err := client.ChatCompletionStream(messages, sarvam.ChatCompletionModelSarvamM, nil)
+	require.NoError(t, err)
+	collected, err := chat.Collect()
+	require.NoError(t, err)
+	assert.Equal(t, "echo: how are you", collected.Choices[0].Message.Content)
+
+	assert.Len(t, server.Requests(), 9)
+}
+
+func TestServerScriptedResponses(t *testing.T) {
+	server := sarvamtest.NewServer(t)
+	client := server.Client()
+
+	server.On(sarvam.EndpointTranslate, sarvamtest.JSON(map[string]any{
+		"request_id":           "fixed",
+		"translated_text":      "नमस्ते",
+		"source_language_code": "en-IN",
+	}))
+	server.Enqueue(sarvam.EndpointTranslate, sarvamtest.JSON(map[string]any{"translated_text": "first"}))
+
+	first, err := client.Translate("hello", sarvam.LanguageEnglish, sarvam.LanguageHindi, nil)
+	require.NoError(t, err)
+	assert.Equal(t, "first", first.TranslatedText)
+
+	for range 2 {
+		resp, err := client.Translate("hello", sarvam.LanguageEnglish, sarvam.LanguageHindi, nil)
+		require.NoError(t, err)
+		assert.Equal(t, "नमस्ते", resp.TranslatedText)
+	}
+
+	server.Handle(sarvam.EndpointTextLID, func(req sarvamtest.Request) sarvamtest.Response {
+		return sarvamtest.JSON(map[string]any{"language_code": "ta-IN", "script_code": "Taml"})
+	})
+	identified, err := client.IdentifyLanguage("வணக்கம்")
+	require.NoError(t, err)
+	assert.Equal(t, sarvam.LanguageTamil, identified.Language)
+}
+
+func TestServerChatStream(t *testing.T) {
+	server := sarvamtest.NewServer(t)
+	server.On(sarvam.EndpointChatCompletions, sarvamtest.ChatStream(
+		sarvam.ChatCompletionChunk{Choices: []sarvam.ChatCompletionChunkChoice{{Delta: sarvam.ChatCompletionDelta{Content: "Hel"}}}},
+		sarvam.ChatCompletionChunk{Choices: []sarvam.ChatCompletionChunkChoice{{Delta: sarvam.ChatCompletionDelta{Content: "lo"}}}},
+	))
+
+	stream, err := server.Client().ChatCompletionStream([]sarvam.Message{sarvam.NewUserMessage("hi")}, sarvam.ChatCompletionModelSarvamM, nil)
+	require.NoError(t, err)
+	var content string
+	for chunk, err := range stream.All() {
+		require.NoError(t, err)
+		content += chunk.Choices[0].Delta.Content
+	}
+	assert.Equal(t, "Hello", content)
+}
+
+func TestServerErrorInjection(t *testing.T) {
+	server := sarvamtest.NewServer(t)
+	client := server.Client(sarvam.WithRetryPolicy(sarvam.RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Millisecond}))
+
+	server.FailNext(sarvam.EndpointTranslate, 2, http.StatusServiceUnavailable, "internal_server_error", "try again")
+	_, err := client.Translate("hello", sarvam.LanguageEnglish, sarvam.LanguageHindi, nil)
+	require.NoError(t, err)
+	server.AssertCalled(t, sarvam.EndpointTranslate, 3)
+
+	server.Enqueue(sarvam.EndpointTextLID, sarvamtest.Error(http.StatusBadRequest, "invalid_request_error", "bad input"))
+	_, err = client.IdentifyLanguage("hello")
+	assert.ErrorIs(t, err, sarvam.ErrValidation)
+	var httpErr *sarvam.HTTPError
+	require.ErrorAs(t, err, &httpErr)
+	assert.Equal(t, "bad input", httpErr.Message)
+}
+
+func TestServerAPIKey(t *testing.T) {
+	server := sarvamtest.NewServer(t)
+
+	wrong := sarvam.NewClient("wrong", sarvam.WithBaseURL(server.URL))
+	_, err := wrong.IdentifyLanguage("hello")
+	assert.ErrorIs(t, err, sarvam.ErrUnauthorized)
+
+	// Rejected requests leave scripted responses and handlers alone.
+	server.Enqueue(sarvam.EndpointTranslate, sarvamtest.JSON(map[string]any{"translated_text": "queued"}))
+	handled := false
+	server.Handle(sarvam.EndpointTextLID, func(req sarvamtest.Request) sarvamtest.Response {
+		handled = true
+		return sarvamtest.JSON(map[string]any{"language_code": "ta-IN"})
+	})
+	_, err = wrong.Translate("hello", sarvam.LanguageEnglish, sarvam.LanguageHindi, nil)
+	assert.ErrorIs(t, err, sarvam.ErrUnauthorized)
+	_, err = wrong.IdentifyLanguage("hello")
+	assert.ErrorIs(t, err, sarvam.ErrUnauthorized)
+	assert.False(t, handled)
+	translation, err := server.Client().Translate("hello", sarvam.LanguageEnglish, sarvam.LanguageHindi, nil)
+	require.NoError(t, err)
+	assert.Equal(t, "queued", translation.TranslatedText)
+
+	server.SetAPIKey("")
+	_, err = sarvam.NewClient("anything", sarvam.WithBaseURL(server.URL)).IdentifyLanguage("hello")
+	assert.NoError(t, err)
+}
+
+func TestServerLatency(t *testing.T) {
+	server := sarvamtest.NewServer(t)
+	client := server.Client()
+
+	server.SetLatency(50 * time.Millisecond)
+	start := time.Now()
+	_, err := client.IdentifyLanguage("hello")
+	require.NoError(t, err)
+	assert.GreaterOrEqual(t, time.Since(start), 50*time.Millisecond)
+
+	server.Enqueue(sarvam.EndpointTextLID, sarvamtest.Response{Delay: time.Second})
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	_, err = client.IdentifyLanguageWithContext(ctx, "hello")
+	assert.True(t, errors.Is(err, sarvam.ErrRequestCanceled) || errors.Is(err, context.DeadlineExceeded), "got %v", err)
+}
+
+func TestServerRecordsRequests(t *testing.T) {
+	server := sarvamtest.NewServer(t)
+	client := server.Client()
+
+	_, err := client.Translate("hello", sarvam.LanguageEnglish, sarvam.LanguageHindi, &sarvam.TranslateParams{})
+	require.NoError(t, err)
+	server.AssertRequest(t, sarvam.EndpointTranslate, "input", "hello")
+	server.AssertRequest(t, sarvam.EndpointTranslate, "target_language_code", sarvam.LanguageHindi)
+
+	_, err = client.SpeechToText(strings.NewReader(testWAV), sarvam.SpeechToTextParams{Language: sarvam.Ptr(sarvam.LanguageTamil)})
+	require.NoError(t, err)
+	req, ok := server.LastRequest(sarvam.EndpointSpeechToText)
+	require.True(t, ok)
+	assert.Equal(t, http.MethodPost, req.Method)
+	assert.Equal(t, "speech.wav", req.FileName)
+	assert.True(t, bytes.Equal([]byte(testWAV), req.File))
+	assert.Equal(t, sarvamtest.DefaultAPIKey, req.Header.Get("api-subscription-key"))
+	server.AssertRequest(t, sarvam.EndpointSpeechToText, "language_code", sarvam.LanguageTamil)
+
+	server.AssertNotCalled(t, sarvam.EndpointChatCompletions)
+	assert.Len(t, server.RequestsTo(sarvam.EndpointTranslate), 1)
+
+	server.Reset()
+	assert.Empty(t, server.Requests())
+}
+
+func TestServerAssertionsReportFailures(t *testing.T) {
+	server := sarvamtest.NewServer(t)
+	_, err := server.Client().IdentifyLanguage("hello")
+	require.NoError(t, err)
+
+	fake := &recordingTB{TB: t}
+	assert.False(t, server.AssertCalled(fake, sarvam.EndpointTextLID, 2))
+	assert.False(t, server.AssertRequest(fake, sarvam.EndpointTextLID, "input", "goodbye"))
+	assert.False(t, server.AssertRequest(fake, sarvam.EndpointTranslate, "input", "hello"))
+	assert.True(t, fake.failed)
+	assert.True(t, server.AssertRequest(t, sarvam.EndpointTextLID, "input", "hello"))
+}
+
+// recordingTB records test failures instead of reporting them.
+type recordingTB struct {
+	testing.TB
+	failed bool
+}
+
+func (r *recordingTB) Helper() {}
+
+func (r *recordingTB) Errorf(format string, args ...any) { r.failed = true }
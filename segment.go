@@ -0,0 +1,134 @@
+package sarvam
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// textSegment is a piece of text together with the whitespace that separates it from the
+// next piece, so that the original spacing can be restored when pieces are put back together.
+type textSegment struct {
+	text  string
+	space string
+}
+
+// sentenceTerminators are the punctuation marks that end a sentence in the scripts of the
+// supported languages.
+const sentenceTerminators = ".?!…" +
+	"।॥" + // Devanagari danda and double danda, shared by most Indic scripts
+	"۔؟" + // Urdu full stop and Arabic question mark
+	"᱾᱿" + // Ol Chiki punctuation used for Santali
+	"꯫" // Meetei Mayek cheikhei used for Manipuri
+
+// clauseTerminators are the punctuation marks after which a sentence may be broken.
+const clauseTerminators = ",;:،؛" // including the Arabic comma and semicolon used for Urdu and Kashmiri
+
+// closingPunctuation may follow a terminator without ending the sentence early.
+const closingPunctuation = "\"')]}»”’"
+
+// chunkText splits text into chunks no longer than maxLength, as measured by length.
+// Chunks are made of whole sentences where possible, falling back to clauses, words and
+// finally characters for longer sentences. Chunks never span a line break. Leading
+// whitespace is returned separately.
+func chunkText(text string, maxLength int, length func(string) int) (leading string, chunks []textSegment) {
+	trimmed := strings.TrimLeftFunc(text, unicode.IsSpace)
+	return text[:len(text)-len(trimmed)], chunkLevel(trimmed, maxLength, length, 0)
+}
+
+// segmentSplitters split text ever more finely, from sentences down to words.
+var segmentSplitters = []func(string) []textSegment{splitSentences, splitClauses, splitWords}
+
+func chunkLevel(text string, maxLength int, length func(string) int, level int) []textSegment {
+	if level == len(segmentSplitters) {
+		return splitCharacters(text, maxLength, length)
+	}
+
+	var chunks []textSegment
+	for _, segment := range segmentSplitters[level](text) {
+		if length(segment.text) > maxLength {
+			pieces := chunkLevel(segment.text, maxLength, length, level+1)
+			pieces[len(pieces)-1].space = segment.space
+			chunks = append(chunks, pieces...)
+			continue
+		}
+		if n := len(chunks); n > 0 && !strings.Contains(chunks[n-1].space, "\n") {
+			if merged := chunks[n-1].text + chunks[n-1].space + segment.text; length(merged) <= maxLength {
+				chunks[n-1] = textSegment{text: merged, space: segment.space}
+				continue
+			}
+		}
+		chunks = append(chunks, segment)
+	}
+	return chunks
+}
+
+// splitSentences splits text after sentence terminators and at line breaks.
+func splitSentences(text string) []textSegment {
+	return splitAtSpaces(text, func(before, space string) bool {
+		return strings.Contains(space, "\n") || endsWithAny(strings.TrimRight(before, closingPunctuation), sentenceTerminators)
+	})
+}
+
+// splitClauses splits text after clause terminators.
+func splitClauses(text string) []textSegment {
+	return splitAtSpaces(text, func(before, space string) bool {
+		return endsWithAny(strings.TrimRight(before, closingPunctuation), clauseTerminators)
+	})
+}
+
+// splitWords splits text at every run of whitespace.
+func splitWords(text string) []textSegment {
+	return splitAtSpaces(text, func(before, space string) bool { return true })
+}
+
+// splitCharacters splits text into pieces no longer than maxLength without breaking characters.
+func splitCharacters(text string, maxLength int, length func(string) int) []textSegment {
+	var pieces []textSegment
+	start := 0
+	for i, r := range text {
+		if i > start && length(text[start:i+utf8.RuneLen(r)]) > maxLength {
+			pieces = append(pieces, textSegment{text: text[start:i]})
+			start = i
+		}
+	}
+	return append(pieces, textSegment{text: text[start:]})
+}
+
+// splitAtSpaces splits text at the runs of whitespace for which boundary returns true.
+// boundary receives the text since the previous split and the run of whitespace.
+func splitAtSpaces(text string, boundary func(before, space string) bool) []textSegment {
+	var segments []textSegment
+	start := 0
+	for i := 0; i < len(text); {
+		r, size := utf8.DecodeRuneInString(text[i:])
+		if !unicode.IsSpace(r) {
+			i += size
+			continue
+		}
+
+		end := i
+		for end < len(text) {
+			r, size := utf8.DecodeRuneInString(text[end:])
+			if !unicode.IsSpace(r) {
+				break
+			}
+			end += size
+		}
+		if end == len(text) || boundary(text[start:i], text[i:end]) {
+			segments = append(segments, textSegment{text: text[start:i], space: text[i:end]})
+			start = end
+		}
+		i = end
+	}
+	if start < len(text) {
+		segments = append(segments, textSegment{text: text[start:]})
+	}
+	return segments
+}
+
+// endsWithAny reports whether the last character of s is one of chars.
+func endsWithAny(s, chars string) bool {
+	r, _ := utf8.DecodeLastRuneInString(s)
+	return r != utf8.RuneError && strings.ContainsRune(chars, r)
+}
@@ -0,0 +1,66 @@
+package sarvam
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func byteLength(s string) int { return len(s) }
+
+func TestSplitSentences(t *testing.T) {
+	segments := splitSentences("राम घर गया। वह खुश था॥ Is it? \"Yes.\" Pi is 3.14 ok\nNext line")
+	assert.Equal(t, []textSegment{
+		{text: "राम घर गया।", space: " "},
+		{text: "वह खुश था॥", space: " "},
+		{text: "Is it?", space: " "},
+		{text: "\"Yes.\"", space: " "},
+		{text: "Pi is 3.14 ok", space: "\n"},
+		{text: "Next line"},
+	}, segments)
+}
+
+func TestSplitSentencesOtherScripts(t *testing.T) {
+	assert.Len(t, splitSentences("یہ کتاب ہے۔ وہ کون ہے؟ ٹھیک"), 3)
+	assert.Len(t, splitSentences("ᱚᱞ ᱪᱤᱠᱤ᱾ ᱥᱟᱱᱛᱟᱲᱤ"), 2)
+}
+
+func TestChunkTextPacksSentences(t *testing.T) {
+	leading, chunks := chunkText("  One. Two. Three.\n\nFour.", 11, byteLength)
+	assert.Equal(t, "  ", leading)
+	assert.Equal(t, []textSegment{
+		{text: "One. Two.", space: " "},
+		{text: "Three.", space: "\n\n"},
+		{text: "Four."},
+	}, chunks)
+}
+
+func TestChunkTextSplitsLongSentences(t *testing.T) {
+	_, chunks := chunkText("alpha beta, gamma delta epsilon", 12, byteLength)
+	assert.Equal(t, []textSegment{
+		{text: "alpha beta,", space: " "},
+		{text: "gamma delta", space: " "},
+		{text: "epsilon"},
+	}, chunks)
+
+	_, chunks = chunkText("नमस्तेनमस्ते", 5, utf8.RuneCountInString)
+	for _, chunk := range chunks {
+		assert.True(t, utf8.ValidString(chunk.text))
+		assert.LessOrEqual(t, utf8.RuneCountInString(chunk.text), 5)
+	}
+}
+
+func TestChunkTextRoundTrip(t *testing.T) {
+	text := strings.Repeat("यह एक लंबा वाक्य है, जिसमें कई शब्द हैं। ", 50) + "\n\nThe end.\n"
+	leading, chunks := chunkText(text, 120, byteLength)
+
+	var rebuilt strings.Builder
+	rebuilt.WriteString(leading)
+	for _, chunk := range chunks {
+		assert.LessOrEqual(t, len(chunk.text), 120)
+		rebuilt.WriteString(chunk.text + chunk.space)
+	}
+	assert.Equal(t, text, rebuilt.String())
+}
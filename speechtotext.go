@@ -1,10 +1,20 @@
 package sarvam
 
 import (
+	"bytes"
+	"cmp"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
+	"math"
 	"net/http"
+	"slices"
+	"strings"
+	"time"
+	"unicode"
+
+	"code.abhai.dev/sarvam/audio"
 )
 
 // Timestamps represents word-level timing information for speech-to-text results.
@@ -22,11 +32,45 @@ type DiarizedEntry struct {
 	SpeakerID        string  `json:"speaker_id"`
 }
 
+// Duration returns how long the entry lasts.
+func (e DiarizedEntry) Duration() time.Duration {
+	return seconds(e.EndTimeSeconds) - seconds(e.StartTimeSeconds)
+}
+
 // DiarizedTranscript represents the complete diarized transcript.
 type DiarizedTranscript struct {
 	Entries []DiarizedEntry `json:"entries"`
 }
 
+// Speakers returns the IDs of the speakers in the order they first speak.
+func (d *DiarizedTranscript) Speakers() []string {
+	var speakers []string
+	for _, entry := range d.Entries {
+		if !slices.Contains(speakers, entry.SpeakerID) {
+			speakers = append(speakers, entry.SpeakerID)
+		}
+	}
+	return speakers
+}
+
+// BySpeaker groups the entries by speaker ID, keeping them in order within each group.
+func (d *DiarizedTranscript) BySpeaker() map[string][]DiarizedEntry {
+	groups := make(map[string][]DiarizedEntry)
+	for _, entry := range d.Entries {
+		groups[entry.SpeakerID] = append(groups[entry.SpeakerID], entry)
+	}
+	return groups
+}
+
+// TalkTime returns the total time each speaker spends speaking.
+func (d *DiarizedTranscript) TalkTime() map[string]time.Duration {
+	talkTime := make(map[string]time.Duration)
+	for _, entry := range d.Entries {
+		talkTime[entry.SpeakerID] += entry.Duration()
+	}
+	return talkTime
+}
+
 // SpeechToTextResponse represents the result of a speech-to-text operation.
 type SpeechToTextResponse struct {
 	RequestId          string              `json:"request_id"`
@@ -43,14 +87,22 @@ func (s *SpeechToTextResponse) String() string {
 
 // SpeechToTextParams contains parameters for speech-to-text conversion.
 type SpeechToTextParams struct {
-	Model          *SpeechToTextModel // Optional: Model to use (default: saarika:v2.5)
-	Language       *Language          // Optional: Language code for the input audio
-	WithTimestamps *bool              // Optional: Whether to include timestamps in response
+	Model           *SpeechToTextModel // Optional: Model to use (default: saarika:v2.5)
+	Language        *Language          // Optional: Language code for the input audio
+	WithTimestamps  *bool              // Optional: Whether to include timestamps in response
+	WithDiarization *bool              // Optional: Whether to identify speakers in the transcript
+	NumSpeakers     *int               // Optional: Expected number of speakers, used with WithDiarization
+	AudioCodec      *AudioCodec        // Optional: Codec of the input audio (default: detected from its header)
 }
 
 // SpeechToText converts speech from an audio file to text.
 func (c *Client) SpeechToText(speech io.Reader, params SpeechToTextParams) (*SpeechToTextResponse, error) {
-	resp, err := c.buildSpeechToTextRequest("/speech-to-text", speech, params)
+	return c.SpeechToTextWithContext(context.Background(), speech, params)
+}
+
+// SpeechToTextWithContext is like SpeechToText but uses ctx to control cancellation and deadlines.
+func (c *Client) SpeechToTextWithContext(ctx context.Context, speech io.Reader, params SpeechToTextParams) (*SpeechToTextResponse, error) {
+	resp, err := c.buildSpeechToTextRequest(ctx, EndpointSpeechToText, speech, params)
 	if err != nil {
 		return nil, err
 	}
@@ -61,7 +113,12 @@ func (c *Client) SpeechToText(speech io.Reader, params SpeechToTextParams) (*Spe
 		return nil, parseAPIError(resp)
 	}
 
-	// Parse the response
+	return decodeSpeechToTextResponse(resp.Body)
+}
+
+// decodeSpeechToTextResponse decodes a speech-to-text result, as returned by the
+// synchronous endpoint and written by batch jobs.
+func decodeSpeechToTextResponse(r io.Reader) (*SpeechToTextResponse, error) {
 	type speechToTextResponse struct {
 		RequestId          string              `json:"request_id"`
 		Transcript         string              `json:"transcript"`
@@ -71,7 +128,7 @@ func (c *Client) SpeechToText(speech io.Reader, params SpeechToTextParams) (*Spe
 	}
 
 	var response speechToTextResponse
-	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+	if err := json.NewDecoder(r).Decode(&response); err != nil {
 		return nil, fmt.Errorf("failed to decode response: %w", err)
 	}
 
@@ -99,9 +156,11 @@ func (s *SpeechToTextTranslateResponse) String() string {
 
 // SpeechToTextTranslateParams contains parameters for speech-to-text-translate conversion.
 type SpeechToTextTranslateParams struct {
-	Prompt     *string                     // Optional: Conversation context to boost model accuracy
-	Model      *SpeechToTextTranslateModel // Optional: Model to use for speech-to-text conversion
-	AudioCodec *AudioCodec                 // Optional: Audio codec to use for speech-to-text conversion
+	Prompt          *string                     // Optional: Conversation context to boost model accuracy
+	Model           *SpeechToTextTranslateModel // Optional: Model to use for speech-to-text conversion
+	AudioCodec      *AudioCodec                 // Optional: Codec of the input audio (default: detected from its header)
+	WithDiarization *bool                       // Optional: Whether to identify speakers in the transcript
+	NumSpeakers     *int                        // Optional: Expected number of speakers, used with WithDiarization
 }
 
 type AudioCodec string
@@ -135,7 +194,12 @@ var (
 
 // SpeechToTextTranslate automatically detects the input language, transcribes the speech, and translates the text to English.
 func (c *Client) SpeechToTextTranslate(speech io.Reader, params SpeechToTextTranslateParams) (*SpeechToTextTranslateResponse, error) {
-	resp, err := c.buildSpeechToTextTranslateRequest("/speech-to-text-translate", speech, params)
+	return c.SpeechToTextTranslateWithContext(context.Background(), speech, params)
+}
+
+// SpeechToTextTranslateWithContext is like SpeechToTextTranslate but uses ctx to control cancellation and deadlines.
+func (c *Client) SpeechToTextTranslateWithContext(ctx context.Context, speech io.Reader, params SpeechToTextTranslateParams) (*SpeechToTextTranslateResponse, error) {
+	resp, err := c.buildSpeechToTextTranslateRequest(ctx, EndpointSpeechToTextTranslate, speech, params)
 	if err != nil {
 		return nil, err
 	}
@@ -166,3 +230,186 @@ func (c *Client) SpeechToTextTranslate(speech io.Reader, params SpeechToTextTran
 		DiarizedTranscript: response.DiarizedTranscript,
 	}, nil
 }
+
+// TranscribeLongOptions contains optional settings for TranscribeLong.
+type TranscribeLongOptions struct {
+	WindowDuration time.Duration // Maximum length of each request; defaults to 25 seconds
+	Overlap        time.Duration // Audio shared by consecutive windows; defaults to 1 second, negative for none
+	Concurrency    int           // Maximum number of concurrent requests; defaults to 4
+	// Format is the format of the input if it is raw PCM rather than a WAV file.
+	Format *audio.Format
+}
+
+const (
+	defaultTranscribeWindow      = 25 * time.Second
+	defaultTranscribeOverlap     = time.Second
+	defaultTranscribeConcurrency = 4
+)
+
+// TranscribeLong converts speech of any length to text by splitting the audio into
+// overlapping windows that fit the synchronous endpoint's duration limit, transcribing
+// them concurrently and stitching the results together.
+//
+// The input must be a WAV file, or raw PCM if opts.Format is set. Windows end in pauses
+// where possible. Timestamps and diarized entries are shifted to their position in the
+// whole recording, and words and entries transcribed twice in the overlap between windows
+// are removed. Speaker IDs are assigned by each window independently. The response
+// carries the request ID of the first window.
+func (c *Client) TranscribeLong(speech io.Reader, params SpeechToTextParams, opts *TranscribeLongOptions) (*SpeechToTextResponse, error) {
+	return c.TranscribeLongWithContext(context.Background(), speech, params, opts)
+}
+
+// TranscribeLongWithContext is like TranscribeLong but uses ctx to control cancellation and deadlines.
+func (c *Client) TranscribeLongWithContext(ctx context.Context, speech io.Reader, params SpeechToTextParams, opts *TranscribeLongOptions) (*SpeechToTextResponse, error) {
+	if opts == nil {
+		opts = &TranscribeLongOptions{}
+	}
+	windowDuration := cmp.Or(opts.WindowDuration, defaultTranscribeWindow)
+	overlap := max(cmp.Or(opts.Overlap, defaultTranscribeOverlap), 0)
+	concurrency := opts.Concurrency
+	if concurrency <= 0 {
+		concurrency = defaultTranscribeConcurrency
+	}
+
+	data, err := io.ReadAll(speech)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read audio: %w", err)
+	}
+	wav := &audio.WAV{Data: data}
+	if opts.Format != nil {
+		wav.Format = *opts.Format
+	} else if wav, err = audio.ParseWAV(data); err != nil {
+		return nil, err
+	}
+
+	windows, err := audio.Split(wav, audio.SplitOptions{
+		MaxDuration: windowDuration,
+		Overlap:     min(overlap, windowDuration/4),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Word timings are needed to remove the overlap between windows. Every window is
+	// uploaded as WAV, whatever the codec of the input.
+	windowParams := params
+	windowParams.WithTimestamps = Ptr(true)
+	windowParams.AudioCodec = nil
+
+	responses := make([]*SpeechToTextResponse, len(windows))
+	err = forEachConcurrently(ctx, len(windows), concurrency, func(ctx context.Context, i int) error {
+		response, err := c.SpeechToTextWithContext(ctx, bytes.NewReader(windows[i].WAV.Bytes()), windowParams)
+		if err != nil {
+			return fmt.Errorf("failed to transcribe window %d of %d: %w", i+1, len(windows), err)
+		}
+		responses[i] = response
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	merged := mergeTranscripts(windows, responses)
+	if params.WithTimestamps == nil || !*params.WithTimestamps {
+		merged.Timestamps = nil
+	}
+	return merged, nil
+}
+
+// mergeTranscripts stitches the transcripts of consecutive overlapping windows together.
+// Each overlap is divided at its midpoint: words and entries starting before it are taken
+// from the earlier window and the rest from the later one.
+func mergeTranscripts(windows []audio.Window, responses []*SpeechToTextResponse) *SpeechToTextResponse {
+	merged := &SpeechToTextResponse{RequestId: responses[0].RequestId}
+	var (
+		transcripts []string
+		timestamps  *Timestamps
+		entries     []DiarizedEntry
+	)
+	for i, response := range responses {
+		offset := windows[i].Start.Seconds()
+		from, until := math.Inf(-1), math.Inf(1)
+		if i > 0 {
+			from = (windows[i].Start + windows[i-1].End).Seconds() / 2
+		}
+		if i < len(windows)-1 {
+			until = (windows[i+1].Start + windows[i].End).Seconds() / 2
+		}
+		keep := func(start float64) bool {
+			return from <= offset+start && offset+start < until
+		}
+
+		if merged.Language == "" {
+			merged.Language = response.Language
+		}
+
+		if response.Timestamps != nil {
+			if timestamps == nil {
+				timestamps = &Timestamps{}
+			}
+			var words []string
+			for j, word := range response.Timestamps.Words {
+				if j >= len(response.Timestamps.StartTimeSeconds) || j >= len(response.Timestamps.EndTimeSeconds) {
+					break
+				}
+				if !keep(response.Timestamps.StartTimeSeconds[j]) {
+					continue
+				}
+				words = append(words, word)
+				timestamps.Words = append(timestamps.Words, word)
+				timestamps.StartTimeSeconds = append(timestamps.StartTimeSeconds, offset+response.Timestamps.StartTimeSeconds[j])
+				timestamps.EndTimeSeconds = append(timestamps.EndTimeSeconds, offset+response.Timestamps.EndTimeSeconds[j])
+			}
+			if len(words) == len(response.Timestamps.Words) {
+				// Nothing was dropped, so keep the punctuation of the original transcript.
+				transcripts = append(transcripts, response.Transcript)
+			} else {
+				transcripts = append(transcripts, strings.Join(words, " "))
+			}
+		} else {
+			transcripts = append(transcripts, removeRepeatedPrefix(strings.Join(transcripts, " "), response.Transcript))
+		}
+
+		if response.DiarizedTranscript != nil {
+			for _, entry := range response.DiarizedTranscript.Entries {
+				if keep(entry.StartTimeSeconds) {
+					entry.StartTimeSeconds += offset
+					entry.EndTimeSeconds += offset
+					entries = append(entries, entry)
+				}
+			}
+		}
+	}
+
+	transcripts = slices.DeleteFunc(transcripts, func(s string) bool { return strings.TrimSpace(s) == "" })
+	merged.Transcript = strings.Join(transcripts, " ")
+	merged.Timestamps = timestamps
+	if entries != nil {
+		merged.DiarizedTranscript = &DiarizedTranscript{Entries: entries}
+	}
+	return merged
+}
+
+// minOverlapWords and maxOverlapWords bound the number of words removeRepeatedPrefix
+// looks for. A single repeated word is as likely to be chance as overlap.
+const (
+	minOverlapWords = 2
+	maxOverlapWords = 20
+)
+
+// removeRepeatedPrefix removes the words at the start of next that repeat the words at
+// the end of previous, for transcripts that have no timings to place the overlap by.
+func removeRepeatedPrefix(previous, next string) string {
+	previousWords, nextWords := strings.Fields(previous), strings.Fields(next)
+	normalize := func(word string) string {
+		return strings.ToLower(strings.TrimFunc(word, unicode.IsPunct))
+	}
+
+	for n := min(len(previousWords), len(nextWords), maxOverlapWords); n >= minOverlapWords; n-- {
+		tail, head := previousWords[len(previousWords)-n:], nextWords[:n]
+		if slices.EqualFunc(tail, head, func(a, b string) bool { return normalize(a) == normalize(b) }) {
+			return strings.Join(nextWords[n:], " ")
+		}
+	}
+	return next
+}
@@ -0,0 +1,262 @@
+package sarvam
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"code.abhai.dev/sarvam/audio"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testSTTFormat = audio.Format{AudioFormat: audio.FormatPCM, Channels: 1, SampleRate: 8000, BitsPerSample: 16}
+
+// countingAudio returns d of audio in which every sample of second n holds the value 100*n.
+func countingAudio(d time.Duration) *audio.WAV {
+	wav := audio.Silence(testSTTFormat, d)
+	for frame := range wav.Frames() {
+		second := frame/int(testSTTFormat.SampleRate) + 1
+		binary.LittleEndian.PutUint16(wav.Data[2*frame:], uint16(100*second))
+	}
+	return wav
+}
+
+// newSTTServer returns a server that transcribes audio made by countingAudio as one word
+// per run of equal samples, naming the second the run belongs to.
+func newSTTServer(t *testing.T, requests *atomic.Int32) *httptest.Server {
+	t.Helper()
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		file, header, err := r.FormFile("file")
+		require.NoError(t, err)
+		data, err := io.ReadAll(file)
+		require.NoError(t, err)
+		wav, err := audio.ParseWAV(data)
+		require.NoError(t, err)
+		assert.Equal(t, "speech.wav", header.Filename)
+		assert.Equal(t, "wav", r.FormValue("audio_codec"))
+		assert.Equal(t, "true", r.FormValue("with_timestamps"))
+
+		var (
+			timestamps Timestamps
+			entries    []DiarizedEntry
+		)
+		seconds := func(frame int) float64 { return float64(frame) / float64(wav.Format.SampleRate) }
+		for start := 0; start < wav.Frames(); {
+			value := binary.LittleEndian.Uint16(wav.Data[2*start:])
+			end := start
+			for end < wav.Frames() && binary.LittleEndian.Uint16(wav.Data[2*end:]) == value {
+				end++
+			}
+			word := fmt.Sprintf("w%d", value/100)
+			timestamps.Words = append(timestamps.Words, word)
+			timestamps.StartTimeSeconds = append(timestamps.StartTimeSeconds, seconds(start))
+			timestamps.EndTimeSeconds = append(timestamps.EndTimeSeconds, seconds(end))
+			entries = append(entries, DiarizedEntry{Transcript: word, StartTimeSeconds: seconds(start), EndTimeSeconds: seconds(end), SpeakerID: "0"})
+			start = end
+		}
+
+		json.NewEncoder(w).Encode(map[string]any{
+			"request_id":          "req",
+			"transcript":          strings.Join(timestamps.Words, " ") + ".",
+			"timestamps":          timestamps,
+			"diarized_transcript": DiarizedTranscript{Entries: entries},
+			"language_code":       "hi-IN",
+		})
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+func TestTranscribeLong(t *testing.T) {
+	var requests atomic.Int32
+	s := newSTTServer(t, &requests)
+	client := NewClient("test", WithBaseURL(s.URL))
+
+	wav := countingAudio(10 * time.Second)
+	response, err := client.TranscribeLong(strings.NewReader(string(wav.Bytes())), SpeechToTextParams{WithTimestamps: Ptr(true)}, &TranscribeLongOptions{
+		WindowDuration: 3 * time.Second,
+		Overlap:        500 * time.Millisecond,
+		Concurrency:    2,
+	})
+	require.NoError(t, err)
+
+	assert.Greater(t, requests.Load(), int32(3))
+	assert.Equal(t, "req", response.RequestId)
+	assert.Equal(t, LanguageHindi, response.Language)
+	// The first window loses no words to the overlap, so its punctuation is kept.
+	assert.Equal(t, "w1 w2 w3. w4 w5 w6 w7 w8 w9 w10", response.Transcript)
+
+	require.NotNil(t, response.Timestamps)
+	require.Len(t, response.Timestamps.StartTimeSeconds, 10)
+	require.Len(t, response.DiarizedTranscript.Entries, 10)
+	for i := range 10 {
+		assert.InDelta(t, float64(i), response.Timestamps.StartTimeSeconds[i], 0.001)
+		assert.InDelta(t, float64(i), response.DiarizedTranscript.Entries[i].StartTimeSeconds, 0.001)
+	}
+}
+
+func TestTranscribeLongWithoutOverlap(t *testing.T) {
+	var requests atomic.Int32
+	s := newSTTServer(t, &requests)
+	client := NewClient("test", WithBaseURL(s.URL))
+
+	wav := countingAudio(10 * time.Second)
+	response, err := client.TranscribeLong(strings.NewReader(string(wav.Bytes())), SpeechToTextParams{}, &TranscribeLongOptions{
+		WindowDuration: 3 * time.Second,
+		Overlap:        -1,
+	})
+	require.NoError(t, err)
+	// With a second of overlap, the windows would start every two seconds rather than three.
+	assert.Equal(t, int32(4), requests.Load())
+	assert.True(t, strings.HasPrefix(response.Transcript, "w1 w2 w3."), response.Transcript)
+	assert.True(t, strings.HasSuffix(response.Transcript, "w10."), response.Transcript)
+}
+
+func TestTranscribeLongPCM(t *testing.T) {
+	var requests atomic.Int32
+	s := newSTTServer(t, &requests)
+	client := NewClient("test", WithBaseURL(s.URL))
+
+	wav := countingAudio(2 * time.Second)
+	response, err := client.TranscribeLong(strings.NewReader(string(wav.Data)), SpeechToTextParams{}, &TranscribeLongOptions{Format: &testSTTFormat})
+	require.NoError(t, err)
+	assert.Equal(t, int32(1), requests.Load())
+	assert.Equal(t, "w1 w2.", response.Transcript)
+	assert.Nil(t, response.Timestamps)
+
+	// Windows are uploaded as WAV even when the input is labelled as raw PCM.
+	response, err = client.TranscribeLong(strings.NewReader(string(wav.Data)), SpeechToTextParams{AudioCodec: &AudioCodecPcmS16le}, &TranscribeLongOptions{Format: &testSTTFormat})
+	require.NoError(t, err)
+	assert.Equal(t, int32(2), requests.Load())
+	assert.Equal(t, "w1 w2.", response.Transcript)
+
+	_, err = client.TranscribeLong(strings.NewReader(string(wav.Data)), SpeechToTextParams{}, nil)
+	assert.ErrorIs(t, err, audio.ErrNotWAV)
+}
+
+func TestRemoveRepeatedPrefix(t *testing.T) {
+	assert.Equal(t, "over the dog", removeRepeatedPrefix("the quick brown fox jumps", "Fox jumps, over the dog"))
+	assert.Equal(t, "a b c", removeRepeatedPrefix("x y z", "a b c"))
+	assert.Equal(t, "a b", removeRepeatedPrefix("", "a b"))
+	// Chunks that happen to meet on the same word keep it.
+	assert.Equal(t, "the cat sat down", removeRepeatedPrefix("I saw the", "the cat sat down"))
+}
+
+// testWAVHeader is the start of a WAV file, enough for the audio format to be recognised.
+const testWAVHeader = "RIFF\x24\x00\x00\x00WAVE"
+
+func TestSpeechToTextDiarizationParams(t *testing.T) {
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, r.ParseMultipartForm(1<<20))
+		assert.Equal(t, "true", r.FormValue("with_diarization"))
+		assert.Equal(t, "2", r.FormValue("num_speakers"))
+		json.NewEncoder(w).Encode(map[string]any{
+			"request_id": "req",
+			"transcript": "hello hi",
+			"diarized_transcript": DiarizedTranscript{Entries: []DiarizedEntry{
+				{Transcript: "hello", StartTimeSeconds: 0, EndTimeSeconds: 1.5, SpeakerID: "0"},
+				{Transcript: "hi", StartTimeSeconds: 1.5, EndTimeSeconds: 2, SpeakerID: "1"},
+			}},
+		})
+	}))
+	defer s.Close()
+	client := NewClient("test", WithBaseURL(s.URL))
+
+	response, err := client.SpeechToText(strings.NewReader(testWAVHeader), SpeechToTextParams{WithDiarization: Ptr(true), NumSpeakers: Ptr(2)})
+	require.NoError(t, err)
+	require.NotNil(t, response.DiarizedTranscript)
+	assert.Len(t, response.DiarizedTranscript.Entries, 2)
+
+	translated, err := client.SpeechToTextTranslate(strings.NewReader(testWAVHeader), SpeechToTextTranslateParams{WithDiarization: Ptr(true), NumSpeakers: Ptr(2)})
+	require.NoError(t, err)
+	assert.Len(t, translated.DiarizedTranscript.Entries, 2)
+
+	_, err = client.SpeechToText(strings.NewReader(testWAVHeader), SpeechToTextParams{NumSpeakers: Ptr(0)})
+	assert.ErrorContains(t, err, "num_speakers must be at least 1")
+}
+
+func TestDiarizedTranscriptHelpers(t *testing.T) {
+	transcript := &DiarizedTranscript{Entries: []DiarizedEntry{
+		{Transcript: "a", StartTimeSeconds: 0, EndTimeSeconds: 2.5, SpeakerID: "1"},
+		{Transcript: "b", StartTimeSeconds: 2.5, EndTimeSeconds: 3, SpeakerID: "0"},
+		{Transcript: "c", StartTimeSeconds: 3, EndTimeSeconds: 4.25, SpeakerID: "1"},
+	}}
+
+	assert.Equal(t, []string{"1", "0"}, transcript.Speakers())
+	groups := transcript.BySpeaker()
+	assert.Equal(t, []DiarizedEntry{transcript.Entries[0], transcript.Entries[2]}, groups["1"])
+	assert.Equal(t, []DiarizedEntry{transcript.Entries[1]}, groups["0"])
+	assert.Equal(t, map[string]time.Duration{"1": 3750 * time.Millisecond, "0": 500 * time.Millisecond}, transcript.TalkTime())
+}
+
+func TestSniffAudioFormat(t *testing.T) {
+	tests := []struct {
+		name   string
+		header string
+		want   audioFormat
+	}{
+		{"wav", testWAVHeader, audioFormatWAV},
+		{"mp3 with ID3 tag", "ID3\x04\x00", audioFormatMP3},
+		{"mp3 frame", "\xff\xfb\x90\x64", audioFormatMP3},
+		{"aac", "\xff\xf1\x50\x80", audioFormatAAC},
+		{"ogg vorbis", "OggS\x00\x02" + strings.Repeat("\x00", 22) + "\x01vorbis", audioFormatOgg},
+		{"ogg opus", "OggS\x00\x02" + strings.Repeat("\x00", 22) + "OpusHead", audioFormatOpus},
+		{"flac", "fLaC\x00\x00\x00\x22", audioFormatFLAC},
+		{"mp4", "\x00\x00\x00\x20ftypisom", audioFormatMP4},
+		{"m4a", "\x00\x00\x00\x20ftypM4A ", audioFormatM4A},
+		{"webm", "\x1a\x45\xdf\xa3\x9f", audioFormatWebM},
+		{"aiff", "FORM\x00\x00\x00\x00AIFF", audioFormatAIFF},
+		{"amr", "#!AMR\n", audioFormatAMR},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := sniffAudioFormat([]byte(tt.header))
+			require.True(t, ok)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+
+	_, ok := sniffAudioFormat([]byte("<html>"))
+	assert.False(t, ok)
+}
+
+func TestSpeechToTextUploadsDetectedFormat(t *testing.T) {
+	type upload struct{ filename, contentType, codec, body string }
+	uploads := make(chan upload, 1)
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		file, header, err := r.FormFile("file")
+		require.NoError(t, err)
+		body, _ := io.ReadAll(file)
+		uploads <- upload{header.Filename, header.Header.Get("Content-Type"), r.FormValue("audio_codec"), string(body)}
+		json.NewEncoder(w).Encode(map[string]string{"transcript": "ok"})
+	}))
+	defer s.Close()
+	client := NewClient("test", WithBaseURL(s.URL))
+
+	_, err := client.SpeechToText(strings.NewReader("ID3\x04 mp3 audio"), SpeechToTextParams{})
+	require.NoError(t, err)
+	assert.Equal(t, upload{"speech.mp3", "audio/mpeg", "mp3", "ID3\x04 mp3 audio"}, <-uploads)
+
+	_, err = client.SpeechToTextTranslate(strings.NewReader("fLaC flac audio"), SpeechToTextTranslateParams{})
+	require.NoError(t, err)
+	assert.Equal(t, upload{"speech.flac", "audio/flac", "flac", "fLaC flac audio"}, <-uploads)
+
+	_, err = client.SpeechToText(strings.NewReader("raw samples"), SpeechToTextParams{AudioCodec: &AudioCodecPcmS16le})
+	require.NoError(t, err)
+	assert.Equal(t, upload{"speech.pcm", "application/octet-stream", "pcm_s16le", "raw samples"}, <-uploads)
+
+	_, err = client.SpeechToText(strings.NewReader("<html>not audio</html>"), SpeechToTextParams{})
+	var formatErr *UnsupportedAudioFormatError
+	require.ErrorAs(t, err, &formatErr)
+	assert.Equal(t, "<html>not audio</html>", string(formatErr.Header))
+	assert.Len(t, uploads, 0)
+}
@@ -0,0 +1,412 @@
+package sarvam
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+
+	"code.abhai.dev/sarvam/internal/websocket"
+)
+
+// SpeechToTextStreamParams contains parameters for a streaming speech-to-text session.
+type SpeechToTextStreamParams struct {
+	Model              *SpeechToTextModel // Optional: Model to use
+	Language           *Language          // Optional: Language code of the audio
+	SampleRate         *int               // Optional: Sample rate of the audio sent (default: 16000)
+	VADSignals         *bool              // Optional: Whether to receive speech start and end events
+	HighVADSensitivity *bool              // Optional: Whether to detect speech boundaries more eagerly
+	FlushSignal        *bool              // Optional: Whether transcripts are finalized only on Flush
+}
+
+// SpeechToTextStreamEventType identifies the kind of a SpeechToTextStreamEvent.
+type SpeechToTextStreamEventType string
+
+const (
+	SpeechToTextStreamEventPartial     SpeechToTextStreamEventType = "partial"      // Interim transcript of the current utterance
+	SpeechToTextStreamEventFinal       SpeechToTextStreamEventType = "final"        // Final transcript of an utterance
+	SpeechToTextStreamEventSpeechStart SpeechToTextStreamEventType = "speech_start" // Voice activity started
+	SpeechToTextStreamEventSpeechEnd   SpeechToTextStreamEventType = "speech_end"   // Voice activity ended
+	SpeechToTextStreamEventReconnected SpeechToTextStreamEventType = "reconnected"  // The connection dropped and was reestablished
+	SpeechToTextStreamEventError       SpeechToTextStreamEventType = "error"        // The server reported an error, or the session failed
+)
+
+// SpeechToTextStreamEvent is an event received during a streaming speech-to-text session.
+type SpeechToTextStreamEvent struct {
+	Type       SpeechToTextStreamEventType
+	RequestId  string
+	Transcript string   // Set for partial and final events
+	Language   Language // Set for partial and final events
+	Err        error    // Set for error events
+}
+
+// StreamError is an error reported by the server during a streaming session.
+type StreamError struct {
+	Code    string
+	Message string
+}
+
+func (e *StreamError) Error() string {
+	if e.Code != "" {
+		return fmt.Sprintf("stream error: code: %s, message: %s", e.Code, e.Message)
+	}
+	return "stream error: " + e.Message
+}
+
+// defaultStreamSampleRate is the sample rate assumed for streamed audio by default.
+const defaultStreamSampleRate = 16000
+
+// SpeechToTextStream is a real-time speech-to-text session over a WebSocket. Audio is
+// sent with Write as 16-bit little-endian mono PCM, and transcripts arrive on Events.
+//
+// If the connection drops, the session reconnects following the client's retry policy
+// and reports a reconnected event; audio written while the connection is down is lost.
+// When reconnecting fails, an error event is sent and Events is closed.
+type SpeechToTextStream struct {
+	client     *Client
+	url        string
+	sampleRate int
+
+	events    chan SpeechToTextStreamEvent
+	done      chan struct{} // Closed by Close
+	closeOnce sync.Once
+	finished  chan struct{} // Closed when the receiving goroutine exits
+
+	mu   sync.Mutex // Guards conn
+	conn *websocket.Conn
+}
+
+// SpeechToTextStream opens a streaming speech-to-text session.
+//
+// The WebSocket connection is opened with the dialer, TLS configuration and proxy of the
+// client's *http.Transport, and the client's timeout limits how long connecting may take.
+// A transport of any other type is not used, since it cannot open a WebSocket; the
+// connection is then made directly with the default settings.
+func (c *Client) SpeechToTextStream(params SpeechToTextStreamParams) (*SpeechToTextStream, error) {
+	return c.SpeechToTextStreamWithContext(context.Background(), params)
+}
+
+// SpeechToTextStreamWithContext is like SpeechToTextStream but uses ctx to control
+// cancellation and deadlines. Canceling ctx also closes the session.
+func (c *Client) SpeechToTextStreamWithContext(ctx context.Context, params SpeechToTextStreamParams) (*SpeechToTextStream, error) {
+	sampleRate := defaultStreamSampleRate
+	if params.SampleRate != nil {
+		sampleRate = *params.SampleRate
+	}
+	if sampleRate <= 0 {
+		return nil, fmt.Errorf("sample rate must be positive, got %d", sampleRate)
+	}
+	model, ok, err := resolveModel(EndpointSpeechToTextStream, modelID(params.Model))
+	if err != nil {
+		return nil, err
+	}
+	if ok {
+		if params.Language != nil {
+			if err := model.validateLanguage("language-code", *params.Language); err != nil {
+				return nil, err
+			}
+		}
+		if err := model.validateSampleRate("sample_rate", sampleRate); err != nil {
+			return nil, err
+		}
+	}
+
+	query := url.Values{}
+	query.Set("sample_rate", strconv.Itoa(sampleRate))
+	query.Set("input_audio_codec", string(AudioCodecPcmS16le))
+	if params.Model != nil {
+		query.Set("model", string(*params.Model))
+	}
+	if params.Language != nil {
+		query.Set("language-code", string(*params.Language))
+	}
+	if params.VADSignals != nil {
+		query.Set("vad_signals", strconv.FormatBool(*params.VADSignals))
+	}
+	if params.HighVADSensitivity != nil {
+		query.Set("high_vad_sensitivity", strconv.FormatBool(*params.HighVADSensitivity))
+	}
+	if params.FlushSignal != nil {
+		query.Set("flush_signal", strconv.FormatBool(*params.FlushSignal))
+	}
+
+	wsURL := c.baseURL + EndpointSpeechToTextStream + "?" + query.Encode()
+	if rest, ok := strings.CutPrefix(wsURL, "http"); ok {
+		wsURL = "ws" + rest
+	}
+
+	conn, err := c.dialWebSocket(ctx, wsURL)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &SpeechToTextStream{
+		client:     c,
+		url:        wsURL,
+		sampleRate: sampleRate,
+		events:     make(chan SpeechToTextStreamEvent, 16),
+		done:       make(chan struct{}),
+		finished:   make(chan struct{}),
+		conn:       conn,
+	}
+	go s.receive(conn)
+	go func() {
+		select {
+		case <-ctx.Done():
+			s.Close()
+		case <-s.done:
+		}
+	}()
+	return s, nil
+}
+
+// dialWebSocket opens a WebSocket connection authenticated like other API requests.
+func (c *Client) dialWebSocket(ctx context.Context, wsURL string) (*websocket.Conn, error) {
+	header := make(http.Header)
+	for key, values := range c.headers {
+		header[key] = append([]string(nil), values...)
+	}
+	if c.userAgent != "" {
+		header.Set("User-Agent", c.userAgent)
+	}
+	header.Set("api-subscription-key", c.apiKey)
+
+	dialCtx := ctx
+	if c.httpClient.Timeout > 0 {
+		var cancel context.CancelFunc
+		dialCtx, cancel = context.WithTimeout(ctx, c.httpClient.Timeout)
+		defer cancel()
+	}
+	conn, resp, err := c.webSocketDialer().Dial(dialCtx, wsURL, header)
+	if err != nil {
+		if errors.Is(err, websocket.ErrBadHandshake) && resp != nil && resp.StatusCode != http.StatusSwitchingProtocols {
+			return nil, parseAPIError(resp)
+		}
+		return nil, contextError(ctx, err)
+	}
+	return conn, nil
+}
+
+// webSocketDialer returns a dialer that connects the way the client's HTTP transport does.
+func (c *Client) webSocketDialer() *websocket.Dialer {
+	roundTripper := c.httpClient.Transport
+	if roundTripper == nil {
+		roundTripper = http.DefaultTransport
+	}
+	transport, ok := roundTripper.(*http.Transport)
+	if !ok {
+		return &websocket.Dialer{}
+	}
+	return &websocket.Dialer{
+		NetDialContext:  transport.DialContext,
+		TLSClientConfig: transport.TLSClientConfig,
+		Proxy:           transport.Proxy,
+	}
+}
+
+// Events returns the channel on which events are delivered. It is closed when the session ends.
+func (s *SpeechToTextStream) Events() <-chan SpeechToTextStreamEvent {
+	return s.events
+}
+
+// Write sends a frame of 16-bit little-endian mono PCM audio.
+func (s *SpeechToTextStream) Write(pcm []byte) (int, error) {
+	type audioMessage struct {
+		Audio struct {
+			Data       string `json:"data"`
+			SampleRate int    `json:"sample_rate"`
+			Encoding   string `json:"encoding"`
+		} `json:"audio"`
+	}
+	var message audioMessage
+	message.Audio.Data = base64.StdEncoding.EncodeToString(pcm)
+	message.Audio.SampleRate = s.sampleRate
+	message.Audio.Encoding = "audio/wav"
+
+	if err := s.send(message); err != nil {
+		return 0, err
+	}
+	return len(pcm), nil
+}
+
+// Flush asks the server to finalize the transcript of the audio sent so far, as when the
+// caller's own voice activity detection decides an utterance has ended.
+func (s *SpeechToTextStream) Flush() error {
+	return s.send(map[string]string{"type": "flush"})
+}
+
+// send writes a JSON message on the current connection.
+func (s *SpeechToTextStream) send(message any) error {
+	data, err := json.Marshal(message)
+	if err != nil {
+		return err
+	}
+	select {
+	case <-s.done:
+		return errors.New("stream is closed")
+	default:
+	}
+
+	s.mu.Lock()
+	conn := s.conn
+	s.mu.Unlock()
+	return conn.WriteMessage(websocket.TextMessage, data)
+}
+
+// Close ends the session and closes the Events channel.
+func (s *SpeechToTextStream) Close() error {
+	s.closeOnce.Do(func() {
+		close(s.done)
+		s.mu.Lock()
+		s.conn.Close()
+		s.mu.Unlock()
+	})
+	<-s.finished
+	return nil
+}
+
+// receive reads messages from conn until the session ends, reconnecting when the
+// connection drops.
+func (s *SpeechToTextStream) receive(conn *websocket.Conn) {
+	defer close(s.finished)
+	defer close(s.events)
+
+	for {
+		_, data, err := conn.ReadMessage()
+		if err == nil {
+			s.handleMessage(data)
+			continue
+		}
+
+		var closeErr *websocket.CloseError
+		if s.isClosed() || errors.As(err, &closeErr) && closeErr.Code == websocket.CloseNormal {
+			return
+		}
+		if conn, err = s.reconnect(); err != nil {
+			if !s.isClosed() {
+				s.emit(SpeechToTextStreamEvent{Type: SpeechToTextStreamEventError, Err: fmt.Errorf("failed to reconnect: %w", err)})
+			}
+			return
+		}
+		s.emit(SpeechToTextStreamEvent{Type: SpeechToTextStreamEventReconnected})
+	}
+}
+
+// reconnect dials the session's URL again, backing off between attempts as the client's
+// retry policy prescribes.
+func (s *SpeechToTextStream) reconnect() (*websocket.Conn, error) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		select {
+		case <-s.done:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	policy := s.client.retryPolicy
+	var err error
+	for attempt := 1; attempt <= max(policy.MaxAttempts, 1); attempt++ {
+		if err := sleep(ctx, policy.backoff(attempt, nil)); err != nil {
+			return nil, err
+		}
+
+		var conn *websocket.Conn
+		if conn, err = s.client.dialWebSocket(ctx, s.url); err == nil {
+			s.mu.Lock()
+			defer s.mu.Unlock()
+			if s.isClosed() {
+				conn.Close()
+				return nil, errors.New("stream is closed")
+			}
+			s.conn = conn
+			return conn, nil
+		}
+
+		var httpErr *HTTPError
+		if errors.As(err, &httpErr) && !policy.retryableStatus(httpErr.StatusCode) {
+			return nil, err
+		}
+	}
+	return nil, err
+}
+
+// handleMessage turns a message from the server into an event.
+func (s *SpeechToTextStream) handleMessage(data []byte) {
+	var message struct {
+		Type string          `json:"type"`
+		Data json.RawMessage `json:"data"`
+	}
+	if err := json.Unmarshal(data, &message); err != nil {
+		s.emit(SpeechToTextStreamEvent{Type: SpeechToTextStreamEventError, Err: fmt.Errorf("failed to decode message: %w", err)})
+		return
+	}
+
+	switch message.Type {
+	case "data":
+		var transcript struct {
+			RequestId    string `json:"request_id"`
+			Transcript   string `json:"transcript"`
+			LanguageCode string `json:"language_code"`
+			IsFinal      *bool  `json:"is_final"`
+		}
+		if err := json.Unmarshal(message.Data, &transcript); err != nil {
+			s.emit(SpeechToTextStreamEvent{Type: SpeechToTextStreamEventError, Err: fmt.Errorf("failed to decode transcript: %w", err)})
+			return
+		}
+		event := SpeechToTextStreamEvent{
+			Type:       SpeechToTextStreamEventFinal,
+			RequestId:  transcript.RequestId,
+			Transcript: transcript.Transcript,
+			Language:   mapLanguageCodeToLanguage(transcript.LanguageCode),
+		}
+		// Transcripts are final unless marked as interim.
+		if transcript.IsFinal != nil && !*transcript.IsFinal {
+			event.Type = SpeechToTextStreamEventPartial
+		}
+		s.emit(event)
+	case "events":
+		var signal struct {
+			SignalType string `json:"signal_type"`
+		}
+		_ = json.Unmarshal(message.Data, &signal)
+		switch signal.SignalType {
+		case "START_SPEECH":
+			s.emit(SpeechToTextStreamEvent{Type: SpeechToTextStreamEventSpeechStart})
+		case "END_SPEECH":
+			s.emit(SpeechToTextStreamEvent{Type: SpeechToTextStreamEventSpeechEnd})
+		}
+	case "error":
+		var apiError struct {
+			Message string `json:"error"`
+			Code    string `json:"code"`
+		}
+		_ = json.Unmarshal(message.Data, &apiError)
+		s.emit(SpeechToTextStreamEvent{Type: SpeechToTextStreamEventError, Err: &StreamError{Message: apiError.Message, Code: apiError.Code}})
+	}
+}
+
+// emit delivers an event unless the session has been closed.
+func (s *SpeechToTextStream) emit(event SpeechToTextStreamEvent) {
+	select {
+	case s.events <- event:
+	case <-s.done:
+	}
+}
+
+// isClosed reports whether Close has been called.
+func (s *SpeechToTextStream) isClosed() bool {
+	select {
+	case <-s.done:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,204 @@
+package sarvam
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"code.abhai.dev/sarvam/internal/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newFakeStreamingSTTServer returns a server that speaks the streaming speech-to-text
+// protocol. Each audio frame produces a partial transcript counting the bytes received,
+// and a flush produces a final transcript. With dropFirst, the first connection is cut
+// after its first audio frame.
+func newFakeStreamingSTTServer(t *testing.T, dropFirst bool) (*httptest.Server, *atomic.Int32) {
+	t.Helper()
+	var connections atomic.Int32
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("api-subscription-key") != "test" {
+			w.WriteHeader(http.StatusForbidden)
+			w.Write([]byte(`{"error":{"message":"invalid key","code":"invalid_api_key_error"}}`))
+			return
+		}
+		assert.Equal(t, EndpointSpeechToTextStream, r.URL.Path)
+		assert.Equal(t, "hi-IN", r.URL.Query().Get("language-code"))
+		assert.Equal(t, "8000", r.URL.Query().Get("sample_rate"))
+
+		conn, err := websocket.Upgrade(w, r)
+		require.NoError(t, err)
+		defer conn.Close()
+		n := connections.Add(1)
+
+		send := func(messageType string, data any) {
+			message, _ := json.Marshal(map[string]any{"type": messageType, "data": data})
+			conn.WriteMessage(websocket.TextMessage, message)
+		}
+		received := 0
+		for {
+			_, data, err := conn.ReadMessage()
+			if err != nil {
+				return
+			}
+			var message struct {
+				Type  string `json:"type"`
+				Audio *struct {
+					Data       string `json:"data"`
+					SampleRate int    `json:"sample_rate"`
+				} `json:"audio"`
+			}
+			require.NoError(t, json.Unmarshal(data, &message))
+
+			switch {
+			case message.Audio != nil:
+				pcm, err := base64.StdEncoding.DecodeString(message.Audio.Data)
+				require.NoError(t, err)
+				if received == 0 {
+					send("events", map[string]string{"signal_type": "START_SPEECH"})
+				}
+				received += len(pcm)
+				if dropFirst && n == 1 {
+					conn.NetConn().Close()
+					return
+				}
+				send("data", map[string]any{"transcript": fmt.Sprintf("%d bytes", received), "language_code": "hi-IN", "is_final": false})
+			case message.Type == "flush":
+				send("events", map[string]string{"signal_type": "END_SPEECH"})
+				send("data", map[string]any{"request_id": "req", "transcript": fmt.Sprintf("%d bytes", received), "language_code": "hi-IN"})
+			case message.Type == "fail":
+				send("error", map[string]string{"error": "bad audio", "code": "invalid_request"})
+			}
+		}
+	}))
+	t.Cleanup(s.Close)
+	return s, &connections
+}
+
+// nextEvent returns the next event of the stream, failing the test after a timeout.
+func nextEvent(t *testing.T, stream *SpeechToTextStream) SpeechToTextStreamEvent {
+	t.Helper()
+	select {
+	case event, ok := <-stream.Events():
+		require.True(t, ok, "events channel closed")
+		return event
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for event")
+		return SpeechToTextStreamEvent{}
+	}
+}
+
+var testStreamParams = SpeechToTextStreamParams{Language: Ptr(LanguageHindi), SampleRate: Ptr(8000), VADSignals: Ptr(true)}
+
+func TestSpeechToTextStream(t *testing.T) {
+	server, _ := newFakeStreamingSTTServer(t, false)
+	client := NewClient("test", WithBaseURL(server.URL))
+
+	stream, err := client.SpeechToTextStream(testStreamParams)
+	require.NoError(t, err)
+	defer stream.Close()
+
+	_, err = stream.Write(make([]byte, 320))
+	require.NoError(t, err)
+	assert.Equal(t, SpeechToTextStreamEvent{Type: SpeechToTextStreamEventSpeechStart}, nextEvent(t, stream))
+	assert.Equal(t, SpeechToTextStreamEvent{Type: SpeechToTextStreamEventPartial, Transcript: "320 bytes", Language: LanguageHindi}, nextEvent(t, stream))
+
+	_, err = stream.Write(make([]byte, 160))
+	require.NoError(t, err)
+	assert.Equal(t, "480 bytes", nextEvent(t, stream).Transcript)
+
+	require.NoError(t, stream.Flush())
+	assert.Equal(t, SpeechToTextStreamEventSpeechEnd, nextEvent(t, stream).Type)
+	assert.Equal(t, SpeechToTextStreamEvent{Type: SpeechToTextStreamEventFinal, RequestId: "req", Transcript: "480 bytes", Language: LanguageHindi}, nextEvent(t, stream))
+
+	require.NoError(t, stream.send(map[string]string{"type": "fail"}))
+	event := nextEvent(t, stream)
+	var streamErr *StreamError
+	require.ErrorAs(t, event.Err, &streamErr)
+	assert.Equal(t, "invalid_request", streamErr.Code)
+
+	require.NoError(t, stream.Close())
+	_, ok := <-stream.Events()
+	assert.False(t, ok)
+	_, err = stream.Write([]byte{0})
+	assert.Error(t, err)
+}
+
+func TestSpeechToTextStreamReconnects(t *testing.T) {
+	server, connections := newFakeStreamingSTTServer(t, true)
+	client := NewClient("test", WithBaseURL(server.URL), WithRetryPolicy(RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Millisecond}))
+
+	stream, err := client.SpeechToTextStream(testStreamParams)
+	require.NoError(t, err)
+	defer stream.Close()
+
+	_, err = stream.Write(make([]byte, 100))
+	require.NoError(t, err)
+	assert.Equal(t, SpeechToTextStreamEventSpeechStart, nextEvent(t, stream).Type)
+	assert.Equal(t, SpeechToTextStreamEventReconnected, nextEvent(t, stream).Type)
+	assert.Equal(t, int32(2), connections.Load())
+
+	_, err = stream.Write(make([]byte, 50))
+	require.NoError(t, err)
+	assert.Equal(t, SpeechToTextStreamEventSpeechStart, nextEvent(t, stream).Type)
+	assert.Equal(t, "50 bytes", nextEvent(t, stream).Transcript)
+}
+
+func TestSpeechToTextStreamReconnectRejected(t *testing.T) {
+	var handshakes atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if handshakes.Add(1) > 1 {
+			// The key was revoked while the stream was open.
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		conn, err := websocket.Upgrade(w, r)
+		require.NoError(t, err)
+		conn.NetConn().Close()
+	}))
+	defer server.Close()
+	client := NewClient("test", WithBaseURL(server.URL), WithRetryPolicy(RetryPolicy{MaxAttempts: 5, InitialBackoff: time.Millisecond}))
+
+	stream, err := client.SpeechToTextStream(testStreamParams)
+	require.NoError(t, err)
+	defer stream.Close()
+
+	event := nextEvent(t, stream)
+	assert.Equal(t, SpeechToTextStreamEventError, event.Type)
+	assert.ErrorIs(t, event.Err, ErrUnauthorized)
+	assert.Equal(t, int32(2), handshakes.Load(), "rejected handshakes must not be retried")
+}
+
+func TestSpeechToTextStreamUsesTransport(t *testing.T) {
+	server, _ := newFakeStreamingSTTServer(t, false)
+	var dials atomic.Int32
+	transport := &http.Transport{DialContext: func(ctx context.Context, network, addr string) (net.Conn, error) {
+		dials.Add(1)
+		return (&net.Dialer{}).DialContext(ctx, network, addr)
+	}}
+	client := NewClient("test", WithBaseURL(server.URL), WithTransport(transport))
+
+	stream, err := client.SpeechToTextStream(testStreamParams)
+	require.NoError(t, err)
+	defer stream.Close()
+	assert.Equal(t, int32(1), dials.Load())
+}
+
+func TestSpeechToTextStreamRejected(t *testing.T) {
+	server, _ := newFakeStreamingSTTServer(t, false)
+	client := NewClient("wrong", WithBaseURL(server.URL))
+
+	_, err := client.SpeechToTextStream(testStreamParams)
+	var httpErr *HTTPError
+	require.ErrorAs(t, err, &httpErr)
+	assert.Equal(t, http.StatusForbidden, httpErr.StatusCode)
+	assert.Equal(t, "invalid_api_key_error", httpErr.Code)
+}
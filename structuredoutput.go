@@ -0,0 +1,214 @@
+package sarvam
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"regexp"
+	"strings"
+)
+
+// ResponseFormat constrains the format of the model's reply.
+type ResponseFormat struct {
+	Type       string                    `json:"type"` // "text", "json_object" or "json_schema"
+	JSONSchema *ResponseFormatJSONSchema `json:"json_schema,omitempty"`
+}
+
+// ResponseFormatJSONSchema describes the schema a "json_schema" response format must follow.
+type ResponseFormatJSONSchema struct {
+	Name        string      `json:"name"`
+	Description string      `json:"description,omitempty"`
+	Schema      *JSONSchema `json:"schema"`
+	Strict      *bool       `json:"strict,omitempty"`
+}
+
+// StructuredOutputOptions contains optional settings for ChatCompletionInto.
+type StructuredOutputOptions struct {
+	// Name identifies the schema in the request. It defaults to the name of the Go type.
+	Name string
+	// MaxAttempts is the number of completions to request before giving up on a reply
+	// that does not match the schema. It defaults to 3.
+	MaxAttempts int
+	// DisableResponseFormat describes the schema in the prompt instead of sending it as
+	// response_format, for models that do not support it.
+	DisableResponseFormat bool
+}
+
+// StructuredOutputError is returned by ChatCompletionInto when no reply matched the schema.
+type StructuredOutputError struct {
+	Attempts int    // Number of completions requested
+	Content  string // Content of the last reply
+	Err      error  // Why the last reply was rejected
+}
+
+func (e *StructuredOutputError) Error() string {
+	return fmt.Sprintf("no valid structured output after %d attempts: %v", e.Attempts, e.Err)
+}
+
+func (e *StructuredOutputError) Unwrap() error {
+	return e.Err
+}
+
+// defaultStructuredOutputAttempts is the number of completions ChatCompletionInto requests by default.
+const defaultStructuredOutputAttempts = 3
+
+// ChatCompletionInto creates a chat completion whose reply is a JSON value matching the
+// schema derived from T with JSONSchemaFor, and decodes it into a T.
+//
+// The schema is sent as response_format; if the API rejects that, it is described in the
+// prompt instead. JSON is extracted from replies wrapped in reasoning blocks or code fences.
+// Replies that do not match the schema are sent back to the model along with the problems
+// found, up to opts.MaxAttempts times.
+func ChatCompletionInto[T any](c *Client, messages []Message, model ChatCompletionModel, req *ChatCompletionParams, opts *StructuredOutputOptions) (T, *ChatCompletionResponse, error) {
+	return ChatCompletionIntoWithContext[T](context.Background(), c, messages, model, req, opts)
+}
+
+// ChatCompletionIntoWithContext is like ChatCompletionInto but uses ctx to control cancellation and deadlines.
+func ChatCompletionIntoWithContext[T any](ctx context.Context, c *Client, messages []Message, model ChatCompletionModel, req *ChatCompletionParams, opts *StructuredOutputOptions) (T, *ChatCompletionResponse, error) {
+	var zero T
+	if opts == nil {
+		opts = &StructuredOutputOptions{}
+	}
+	maxAttempts := opts.MaxAttempts
+	if maxAttempts <= 0 {
+		maxAttempts = defaultStructuredOutputAttempts
+	}
+	name := opts.Name
+	if name == "" {
+		name = schemaName(reflect.TypeFor[T]())
+	}
+	schema := JSONSchemaFor[T]()
+
+	var params ChatCompletionParams
+	if req != nil {
+		params = *req
+	}
+	useResponseFormat := !opts.DisableResponseFormat
+	history := append([]Message(nil), messages...)
+	if useResponseFormat {
+		params.ResponseFormat = &ResponseFormat{
+			Type:       "json_schema",
+			JSONSchema: &ResponseFormatJSONSchema{Name: name, Schema: schema},
+		}
+	} else {
+		history = withSchemaInstructions(history, schema)
+	}
+
+	var lastErr error
+	var content string
+	for attempt := 1; attempt <= maxAttempts; attempt++ {
+		response, err := c.ChatCompletionWithContext(ctx, history, model, &params)
+		if err != nil && useResponseFormat && isResponseFormatUnsupported(err) {
+			useResponseFormat = false
+			params.ResponseFormat = nil
+			history = withSchemaInstructions(history, schema)
+			response, err = c.ChatCompletionWithContext(ctx, history, model, &params)
+		}
+		if err != nil {
+			return zero, nil, err
+		}
+
+		// Decode each reply into a fresh value, so that a reply that fails to decode does not
+		// leave fields behind for the next one.
+		var result T
+		content = response.GetFirstChoiceContent()
+		if lastErr = decodeStructuredOutput(content, schema, &result); lastErr == nil {
+			return result, response, nil
+		}
+
+		history = append(history,
+			NewAssistantMessage(content),
+			NewUserMessage(fmt.Sprintf("Your reply was not valid: %v. Reply again with only a JSON value that matches the schema.", lastErr)),
+		)
+	}
+	return zero, nil, &StructuredOutputError{Attempts: maxAttempts, Content: content, Err: lastErr}
+}
+
+// decodeStructuredOutput extracts a JSON value from content, validates it against schema
+// and decodes it into v.
+func decodeStructuredOutput(content string, schema *JSONSchema, v any) error {
+	data := extractJSON(content)
+	if data == "" {
+		return errors.New("no JSON value found")
+	}
+
+	decoder := json.NewDecoder(strings.NewReader(data))
+	decoder.UseNumber()
+	var value any
+	if err := decoder.Decode(&value); err != nil {
+		return fmt.Errorf("invalid JSON: %w", err)
+	}
+	if err := schema.Validate(value); err != nil {
+		return err
+	}
+	return json.Unmarshal([]byte(data), v)
+}
+
+var codeFencePattern = regexp.MustCompile("(?s)```(?:json|JSON)?\\s*\\n?(.*?)```")
+
+// extractJSON returns the JSON value in content, skipping reasoning blocks, code fences and
+// any prose around the value. It returns an empty string if there is none.
+func extractJSON(content string) string {
+	_, content = splitReasoning(content, false)
+	if match := codeFencePattern.FindStringSubmatch(content); match != nil {
+		content = match[1]
+	}
+	content = strings.TrimSpace(content)
+	if json.Valid([]byte(content)) {
+		return content
+	}
+
+	for start := 0; start < len(content); start++ {
+		i := strings.IndexAny(content[start:], "{[")
+		if i < 0 {
+			break
+		}
+		start += i
+		var raw json.RawMessage
+		if err := json.NewDecoder(strings.NewReader(content[start:])).Decode(&raw); err == nil {
+			return string(bytes.TrimSpace(raw))
+		}
+	}
+	return ""
+}
+
+// withSchemaInstructions returns messages with instructions to reply with JSON matching
+// schema added to the system prompt.
+func withSchemaInstructions(messages []Message, schema *JSONSchema) []Message {
+	schemaJSON, _ := json.Marshal(schema)
+	instructions := "Reply with only a JSON value that matches this JSON schema, without any other text:\n" + string(schemaJSON)
+
+	messages = append([]Message(nil), messages...)
+	if len(messages) > 0 && messages[0].Role == string(MessageRoleSystem) {
+		messages[0].Content += "\n\n" + instructions
+		return messages
+	}
+	return append([]Message{NewSystemMessage(instructions)}, messages...)
+}
+
+// isResponseFormatUnsupported reports whether err is the API rejecting the response_format parameter.
+func isResponseFormatUnsupported(err error) bool {
+	var httpErr *HTTPError
+	if !errors.As(err, &httpErr) {
+		return false
+	}
+	if httpErr.StatusCode != http.StatusBadRequest && httpErr.StatusCode != http.StatusUnprocessableEntity {
+		return false
+	}
+	return strings.Contains(httpErr.Message, "response_format")
+}
+
+// schemaName returns a name for the schema of t that is accepted by the API.
+func schemaName(t reflect.Type) string {
+	for t.Kind() == reflect.Pointer || t.Kind() == reflect.Slice {
+		t = t.Elem()
+	}
+	if t.Name() == "" {
+		return "response"
+	}
+	return t.Name()
+}
@@ -0,0 +1,207 @@
+package sarvam
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testReview struct {
+	Sentiment string   `json:"sentiment" enum:"positive,negative,neutral"`
+	Score     int      `json:"score" description:"Score from 1 to 5"`
+	Tags      []string `json:"tags,omitempty"`
+	Author    *struct {
+		Name string `json:"name"`
+	} `json:"author,omitempty"`
+}
+
+type testNode struct {
+	Value    string      `json:"value"`
+	Children []*testNode `json:"children"`
+	internal int
+}
+
+func TestJSONSchemaFor(t *testing.T) {
+	schema := JSONSchemaFor[testReview]()
+	data, err := json.Marshal(schema)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{
+		"type": "object",
+		"properties": {
+			"sentiment": {"type": "string", "enum": ["positive", "negative", "neutral"]},
+			"score": {"type": "integer", "description": "Score from 1 to 5"},
+			"tags": {"type": "array", "items": {"type": "string"}, "nullable": true},
+			"author": {"type": "object", "properties": {"name": {"type": "string"}}, "required": ["name"], "additionalProperties": false, "nullable": true}
+		},
+		"required": ["sentiment", "score"],
+		"additionalProperties": false
+	}`, string(data))
+}
+
+func TestJSONSchemaForRecursiveAndSpecialTypes(t *testing.T) {
+	schema := JSONSchemaFor[testNode]()
+	assert.Equal(t, &JSONSchema{Nullable: true}, schema.Properties["children"].Items)
+	assert.NotContains(t, schema.Properties, "internal")
+
+	assert.Equal(t, &JSONSchema{Type: "string", Format: "date-time"}, JSONSchemaFor[time.Time]())
+	assert.Equal(t, &JSONSchema{Type: "string", Nullable: true}, JSONSchemaFor[[]byte]())
+	assert.Equal(t, &JSONSchema{Type: "object", AdditionalProperties: &JSONSchema{Type: "number"}, Nullable: true}, JSONSchemaFor[map[string]float64]())
+}
+
+func TestJSONSchemaValidate(t *testing.T) {
+	schema := JSONSchemaFor[testReview]()
+	decode := func(s string) any {
+		var v any
+		require.NoError(t, json.Unmarshal([]byte(s), &v))
+		return v
+	}
+
+	assert.NoError(t, schema.Validate(decode(`{"sentiment":"positive","score":4,"tags":["food"]}`)))
+
+	err := schema.Validate(decode(`{"sentiment":"great","score":4.5,"tags":[1],"extra":true}`))
+	var validationErr *SchemaValidationError
+	require.ErrorAs(t, err, &validationErr)
+	assert.ElementsMatch(t, []string{
+		`$.sentiment: must be one of [positive negative neutral]`,
+		`$.score: expected integer, got 4.5`,
+		`$.tags[0]: expected string, got number`,
+		`$: unexpected property "extra"`,
+	}, validationErr.Problems)
+
+	err = schema.Validate(decode(`{"score":1}`))
+	assert.ErrorContains(t, err, `missing required property "sentiment"`)
+
+	err = schema.Validate(decode(`{"sentiment":null,"score":4}`))
+	assert.ErrorContains(t, err, `$.sentiment: must be one of`)
+}
+
+func TestJSONSchemaValidateNull(t *testing.T) {
+	type value struct {
+		Tags   []string       `json:"tags"`
+		P      *int           `json:"p"`
+		Labels map[string]int `json:"labels"`
+		N      int            `json:"n"`
+	}
+	schema := JSONSchemaFor[value]()
+
+	// Nil values are encoded as null, and must be accepted back.
+	data, err := json.Marshal(value{})
+	require.NoError(t, err)
+	var decoded any
+	require.NoError(t, json.Unmarshal(data, &decoded))
+	assert.NoError(t, schema.Validate(decoded))
+
+	require.NoError(t, json.Unmarshal([]byte(`{"tags":null,"p":null,"labels":null,"n":null}`), &decoded))
+	assert.EqualError(t, schema.Validate(decoded), "value does not match schema: $.n: expected integer, got null")
+}
+
+func TestExtractJSON(t *testing.T) {
+	tests := []struct {
+		content string
+		want    string
+	}{
+		{`{"a":1}`, `{"a":1}`},
+		{"<think>\nThe user wants {json}.\n</think>\n\n{\"a\":1}", `{"a":1}`},
+		{"Here you go:\n```json\n{\"a\":1}\n```\nAnything else?", `{"a":1}`},
+		{"Sure [see below]: [1, 2]", `[1, 2]`},
+		{"no json here", ``},
+	}
+	for _, test := range tests {
+		assert.Equal(t, test.want, extractJSON(test.content), test.content)
+	}
+}
+
+func TestChatCompletionInto(t *testing.T) {
+	var requests []chatCompletionRequest
+	replies := []string{
+		`<think>Let me think.</think>{"sentiment":"amazing","score":5}`,
+		"```json\n{\"sentiment\":\"positive\",\"score\":5}\n```",
+	}
+	httpTestServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req chatCompletionRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		requests = append(requests, req)
+		json.NewEncoder(w).Encode(ChatCompletionResponse{Choices: []ChatCompletionChoice{{Message: NewAssistantMessage(replies[len(requests)-1])}}})
+	}))
+	defer httpTestServer.Close()
+
+	client := NewClient("test", WithBaseURL(httpTestServer.URL))
+	review, response, err := ChatCompletionInto[testReview](client, []Message{NewUserMessage("Review: loved the dosa")}, ChatCompletionModelSarvamM, nil, nil)
+	require.NoError(t, err)
+	assert.NotNil(t, response)
+	assert.Equal(t, "positive", review.Sentiment)
+	assert.Equal(t, 5, review.Score)
+
+	require.Len(t, requests, 2)
+	require.NotNil(t, requests[0].ResponseFormat)
+	assert.Equal(t, "json_schema", requests[0].ResponseFormat.Type)
+	assert.Equal(t, "testReview", requests[0].ResponseFormat.JSONSchema.Name)
+	require.Len(t, requests[1].Messages, 3)
+	assert.Contains(t, requests[1].Messages[2].Content, "must be one of")
+}
+
+func TestChatCompletionIntoDiscardsFailedAttempts(t *testing.T) {
+	type small struct {
+		Name  string `json:"name,omitempty"`
+		Count int8   `json:"count"`
+	}
+	replies := []string{`{"name":"first","count":300}`, `{"count":1}`}
+	requests := 0
+	httpTestServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		json.NewEncoder(w).Encode(ChatCompletionResponse{Choices: []ChatCompletionChoice{{Message: NewAssistantMessage(replies[requests-1])}}})
+	}))
+	defer httpTestServer.Close()
+
+	client := NewClient("test", WithBaseURL(httpTestServer.URL))
+	result, _, err := ChatCompletionInto[small](client, []Message{NewUserMessage("?")}, ChatCompletionModelSarvamM, nil, nil)
+	require.NoError(t, err)
+	assert.Equal(t, 2, requests)
+	// The first reply matched the schema but overflowed Count while setting Name.
+	assert.Equal(t, small{Count: 1}, result)
+}
+
+func TestChatCompletionIntoFallsBackToPrompt(t *testing.T) {
+	var requests []chatCompletionRequest
+	httpTestServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req chatCompletionRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		requests = append(requests, req)
+		if req.ResponseFormat != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"error":{"message":"body.response_format : Extra inputs are not permitted","code":"invalid_request_error"}}`))
+			return
+		}
+		w.Write([]byte(`{"choices":[{"message":{"role":"assistant","content":"{\"sentiment\":\"neutral\",\"score\":3}"}}]}`))
+	}))
+	defer httpTestServer.Close()
+
+	client := NewClient("test", WithBaseURL(httpTestServer.URL))
+	review, _, err := ChatCompletionInto[testReview](client, []Message{NewSystemMessage("You review food."), NewUserMessage("It was okay")}, ChatCompletionModelSarvamM, nil, nil)
+	require.NoError(t, err)
+	assert.Equal(t, "neutral", review.Sentiment)
+
+	require.Len(t, requests, 2)
+	assert.Nil(t, requests[1].ResponseFormat)
+	assert.Contains(t, requests[1].Messages[0].Content, "You review food.")
+	assert.Contains(t, requests[1].Messages[0].Content, `"sentiment"`)
+}
+
+func TestChatCompletionIntoGivesUp(t *testing.T) {
+	httpTestServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"choices":[{"message":{"role":"assistant","content":"I cannot do that."}}]}`))
+	}))
+	defer httpTestServer.Close()
+
+	client := NewClient("test", WithBaseURL(httpTestServer.URL))
+	_, _, err := ChatCompletionInto[testReview](client, []Message{NewUserMessage("?")}, ChatCompletionModelSarvamM, nil, &StructuredOutputOptions{MaxAttempts: 2, DisableResponseFormat: true})
+	var outputErr *StructuredOutputError
+	require.ErrorAs(t, err, &outputErr)
+	assert.Equal(t, 2, outputErr.Attempts)
+	assert.Equal(t, "I cannot do that.", outputErr.Content)
+}
@@ -0,0 +1,225 @@
+package sarvam
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// ErrNoTimestamps is returned when subtitles are requested for a transcript without timings.
+var ErrNoTimestamps = errors.New("transcript has no timestamps, set WithTimestamps")
+
+// SubtitleOptions controls how words are grouped into subtitle cues.
+type SubtitleOptions struct {
+	MaxLineLength int           // Maximum characters per line; defaults to 42
+	MaxLines      int           // Maximum lines per cue; defaults to 2
+	MaxDuration   time.Duration // Maximum length of a cue; defaults to 7 seconds
+	MaxPause      time.Duration // Silence between words that starts a new cue; defaults to 1 second
+	// SpeakerLabel returns the label shown for a speaker of a diarized transcript.
+	// It defaults to "Speaker <id>".
+	SpeakerLabel func(speakerID string) string
+}
+
+const (
+	defaultSubtitleLineLength = 42
+	defaultSubtitleLines      = 2
+	defaultSubtitleDuration   = 7 * time.Second
+	defaultSubtitlePause      = time.Second
+)
+
+// withDefaults returns a copy of the options with unset fields filled in.
+func (o *SubtitleOptions) withDefaults() SubtitleOptions {
+	var opts SubtitleOptions
+	if o != nil {
+		opts = *o
+	}
+	if opts.MaxLineLength <= 0 {
+		opts.MaxLineLength = defaultSubtitleLineLength
+	}
+	if opts.MaxLines <= 0 {
+		opts.MaxLines = defaultSubtitleLines
+	}
+	if opts.MaxDuration <= 0 {
+		opts.MaxDuration = defaultSubtitleDuration
+	}
+	if opts.MaxPause <= 0 {
+		opts.MaxPause = defaultSubtitlePause
+	}
+	if opts.SpeakerLabel == nil {
+		opts.SpeakerLabel = func(speakerID string) string { return "Speaker " + speakerID }
+	}
+	return opts
+}
+
+// Cue is a single subtitle, shown from Start to End.
+type Cue struct {
+	Start   time.Duration
+	End     time.Duration
+	Lines   []string
+	Speaker string // Label of the speaker, empty if the transcript is not diarized
+}
+
+// Text returns the lines of the cue joined by newlines.
+func (c Cue) Text() string {
+	return strings.Join(c.Lines, "\n")
+}
+
+// Cues groups the words of the transcript into subtitle cues. A new cue is started when
+// the current one would exceed the line limits or MaxDuration, when the pause before a word
+// exceeds MaxPause, or when the speaker changes.
+//
+// Without Timestamps, each entry of the DiarizedTranscript becomes a cue. If neither is
+// present, ErrNoTimestamps is returned.
+func (s *SpeechToTextResponse) Cues(opts *SubtitleOptions) ([]Cue, error) {
+	o := opts.withDefaults()
+	if s.Timestamps == nil || len(s.Timestamps.Words) == 0 {
+		if s.DiarizedTranscript == nil || len(s.DiarizedTranscript.Entries) == 0 {
+			return nil, ErrNoTimestamps
+		}
+		return entryCues(s.DiarizedTranscript.Entries, o), nil
+	}
+
+	var (
+		cues    []Cue
+		current *Cue
+	)
+	ts := s.Timestamps
+	for i, word := range ts.Words {
+		if i >= len(ts.StartTimeSeconds) || i >= len(ts.EndTimeSeconds) {
+			break
+		}
+		start, end := seconds(ts.StartTimeSeconds[i]), seconds(ts.EndTimeSeconds[i])
+		speaker := ""
+		if s.DiarizedTranscript != nil {
+			if id, ok := speakerAt(s.DiarizedTranscript.Entries, (start+end)/2); ok {
+				speaker = o.SpeakerLabel(id)
+			}
+		}
+
+		if current != nil && (speaker != current.Speaker ||
+			start-current.End > o.MaxPause ||
+			end-current.Start > o.MaxDuration ||
+			!appendWord(current, word, o)) {
+			cues = append(cues, *current)
+			current = nil
+		}
+		if current == nil {
+			current = &Cue{Start: start, Speaker: speaker}
+			appendWord(current, word, o)
+		}
+		current.End = end
+	}
+	if current != nil {
+		cues = append(cues, *current)
+	}
+	return cues, nil
+}
+
+// appendWord adds word to the last line of cue, or to a new line if it does not fit,
+// and reports whether the cue had room for it.
+func appendWord(cue *Cue, word string, opts SubtitleOptions) bool {
+	if n := len(cue.Lines); n > 0 {
+		if utf8.RuneCountInString(cue.Lines[n-1])+1+utf8.RuneCountInString(word) <= opts.MaxLineLength {
+			cue.Lines[n-1] += " " + word
+			return true
+		}
+		if n >= opts.MaxLines {
+			return false
+		}
+	}
+	cue.Lines = append(cue.Lines, word)
+	return true
+}
+
+// entryCues returns a cue for every diarized entry, wrapped to the line length.
+func entryCues(entries []DiarizedEntry, opts SubtitleOptions) []Cue {
+	cues := make([]Cue, 0, len(entries))
+	for _, entry := range entries {
+		cue := Cue{
+			Start:   seconds(entry.StartTimeSeconds),
+			End:     seconds(entry.EndTimeSeconds),
+			Speaker: opts.SpeakerLabel(entry.SpeakerID),
+		}
+		wrap := opts
+		wrap.MaxLines = int(^uint(0) >> 1)
+		for _, word := range strings.Fields(entry.Transcript) {
+			appendWord(&cue, word, wrap)
+		}
+		cues = append(cues, cue)
+	}
+	return cues
+}
+
+// speakerAt returns the speaker of the diarized entry covering t, or of the nearest entry.
+func speakerAt(entries []DiarizedEntry, t time.Duration) (string, bool) {
+	var (
+		speaker string
+		nearest = time.Duration(-1)
+	)
+	for _, entry := range entries {
+		start, end := seconds(entry.StartTimeSeconds), seconds(entry.EndTimeSeconds)
+		distance := max(start-t, t-end, 0)
+		if nearest < 0 || distance < nearest {
+			speaker, nearest = entry.SpeakerID, distance
+		}
+	}
+	return speaker, nearest >= 0
+}
+
+// seconds converts a time in seconds to a time.Duration, rounded to the millisecond.
+func seconds(s float64) time.Duration {
+	return time.Duration(s*1000+0.5) * time.Millisecond
+}
+
+// WriteSRT writes the transcript as SubRip subtitles, prefixing cues with the speaker
+// label when the transcript is diarized.
+func (s *SpeechToTextResponse) WriteSRT(w io.Writer, opts *SubtitleOptions) error {
+	cues, err := s.Cues(opts)
+	if err != nil {
+		return err
+	}
+
+	bw := bufio.NewWriter(w)
+	for i, cue := range cues {
+		text := cue.Text()
+		if cue.Speaker != "" {
+			text = cue.Speaker + ": " + text
+		}
+		fmt.Fprintf(bw, "%d\n%s --> %s\n%s\n\n", i+1, formatCueTime(cue.Start, ','), formatCueTime(cue.End, ','), text)
+	}
+	return bw.Flush()
+}
+
+// WriteWebVTT writes the transcript as WebVTT subtitles, marking the speaker of each cue
+// with a voice tag when the transcript is diarized.
+func (s *SpeechToTextResponse) WriteWebVTT(w io.Writer, opts *SubtitleOptions) error {
+	cues, err := s.Cues(opts)
+	if err != nil {
+		return err
+	}
+
+	bw := bufio.NewWriter(w)
+	bw.WriteString("WEBVTT\n\n")
+	for _, cue := range cues {
+		text := vttEscaper.Replace(cue.Text())
+		if cue.Speaker != "" {
+			text = "<v " + vttEscaper.Replace(cue.Speaker) + ">" + text
+		}
+		fmt.Fprintf(bw, "%s --> %s\n%s\n\n", formatCueTime(cue.Start, '.'), formatCueTime(cue.End, '.'), text)
+	}
+	return bw.Flush()
+}
+
+// vttEscaper escapes the characters that have a special meaning in WebVTT cue text.
+var vttEscaper = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;")
+
+// formatCueTime formats d as hh:mm:ss followed by sep and milliseconds.
+func formatCueTime(d time.Duration, sep byte) string {
+	d = max(d, 0)
+	ms := d.Milliseconds()
+	return fmt.Sprintf("%02d:%02d:%02d%c%03d", ms/3600000, ms/60000%60, ms/1000%60, sep, ms%1000)
+}
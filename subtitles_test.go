@@ -0,0 +1,72 @@
+package sarvam
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func subtitleResponse() *SpeechToTextResponse {
+	return &SpeechToTextResponse{
+		Timestamps: &Timestamps{
+			Words:            []string{"नमस्ते", "आप", "कैसे", "हैं", "मैं", "ठीक", "हूँ"},
+			StartTimeSeconds: []float64{0.0, 0.5, 0.8, 1.1, 3.0, 3.4, 3.7},
+			EndTimeSeconds:   []float64{0.4, 0.7, 1.0, 1.4, 3.3, 3.6, 4.0},
+		},
+	}
+}
+
+func TestCuesSplitOnPause(t *testing.T) {
+	cues, err := subtitleResponse().Cues(nil)
+	require.NoError(t, err)
+	require.Len(t, cues, 2)
+	assert.Equal(t, Cue{Start: 0, End: 1400 * time.Millisecond, Lines: []string{"नमस्ते आप कैसे हैं"}}, cues[0])
+	assert.Equal(t, Cue{Start: 3 * time.Second, End: 4 * time.Second, Lines: []string{"मैं ठीक हूँ"}}, cues[1])
+}
+
+func TestCuesLineAndDurationLimits(t *testing.T) {
+	cues, err := subtitleResponse().Cues(&SubtitleOptions{MaxLineLength: 10, MaxLines: 1, MaxPause: time.Minute})
+	require.NoError(t, err)
+	assert.Equal(t, "नमस्ते आप", cues[0].Text())
+	assert.Equal(t, "कैसे हैं", cues[1].Text())
+
+	cues, err = subtitleResponse().Cues(&SubtitleOptions{MaxLineLength: 10, MaxPause: time.Minute, MaxDuration: 2 * time.Second})
+	require.NoError(t, err)
+	assert.Equal(t, "नमस्ते आप\nकैसे हैं", cues[0].Text())
+	assert.Len(t, cues, 2)
+}
+
+func TestCuesSpeakers(t *testing.T) {
+	response := subtitleResponse()
+	response.DiarizedTranscript = &DiarizedTranscript{Entries: []DiarizedEntry{
+		{Transcript: "नमस्ते आप कैसे", StartTimeSeconds: 0, EndTimeSeconds: 1.0, SpeakerID: "0"},
+		{Transcript: "हैं", StartTimeSeconds: 1.05, EndTimeSeconds: 1.4, SpeakerID: "1"},
+		{Transcript: "मैं ठीक हूँ", StartTimeSeconds: 3.0, EndTimeSeconds: 4.0, SpeakerID: "0"},
+	}}
+
+	var srt strings.Builder
+	require.NoError(t, response.WriteSRT(&srt, nil))
+	assert.Equal(t, "1\n00:00:00,000 --> 00:00:01,000\nSpeaker 0: नमस्ते आप कैसे\n\n"+
+		"2\n00:00:01,100 --> 00:00:01,400\nSpeaker 1: हैं\n\n"+
+		"3\n00:00:03,000 --> 00:00:04,000\nSpeaker 0: मैं ठीक हूँ\n\n", srt.String())
+
+	var vtt strings.Builder
+	require.NoError(t, response.WriteWebVTT(&vtt, &SubtitleOptions{SpeakerLabel: func(id string) string { return "S<" + id + ">" }}))
+	assert.True(t, strings.HasPrefix(vtt.String(), "WEBVTT\n\n00:00:00.000 --> 00:00:01.000\n<v S&lt;0&gt;>नमस्ते आप कैसे\n\n"))
+}
+
+func TestCuesFromDiarizedEntries(t *testing.T) {
+	response := &SpeechToTextResponse{DiarizedTranscript: &DiarizedTranscript{Entries: []DiarizedEntry{
+		{Transcript: "hello there", StartTimeSeconds: 61.5, EndTimeSeconds: 3723.25, SpeakerID: "A"},
+	}}}
+
+	var vtt strings.Builder
+	require.NoError(t, response.WriteWebVTT(&vtt, nil))
+	assert.Equal(t, "WEBVTT\n\n00:01:01.500 --> 01:02:03.250\n<v Speaker A>hello there\n\n", vtt.String())
+
+	_, err := (&SpeechToTextResponse{Transcript: "no timings"}).Cues(nil)
+	assert.ErrorIs(t, err, ErrNoTimestamps)
+}
@@ -1,9 +1,11 @@
 package sarvam
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"strings"
 )
 
 // SpeakerGender represents the gender of the speaker for better translations.
@@ -72,19 +74,25 @@ type TranslateParams struct {
 	NumeralsFormat      *NumeralsFormat
 }
 
-// TranslateWithParams converts text from one language to another with custom parameters.
+// Translate converts text from one language to another with custom parameters.
 func (c *Client) Translate(input string, sourceLanguageCode, targetLanguageCode Language, params *TranslateParams) (*TranslationResponse, error) {
-	// Validate input length based on model
-	maxLength := 2000 // Default for sarvam-translate:v1
-	if params != nil && params.Model != nil && *params.Model == TranslationModelMayuraV1 {
-		maxLength = 1000
-	}
+	return c.TranslateWithContext(context.Background(), input, sourceLanguageCode, targetLanguageCode, params)
+}
 
-	if l := len(input); l > maxLength {
-		return nil, &ErrInputTooLong{
-			InputLength: l,
-			MaxLength:   maxLength,
-		}
+// TranslateWithContext is like Translate but uses ctx to control cancellation and deadlines.
+func (c *Client) TranslateWithContext(ctx context.Context, input string, sourceLanguageCode, targetLanguageCode Language, params *TranslateParams) (*TranslationResponse, error) {
+	model, err := translateModel(params)
+	if err != nil {
+		return nil, err
+	}
+	if err := model.validateInput(input); err != nil {
+		return nil, err
+	}
+	if err := model.validateLanguage("source_language_code", sourceLanguageCode); err != nil {
+		return nil, err
+	}
+	if err := model.validateLanguage("target_language_code", targetLanguageCode); err != nil {
+		return nil, err
 	}
 
 	var reqBody = map[string]any{
@@ -115,7 +123,7 @@ func (c *Client) Translate(input string, sourceLanguageCode, targetLanguageCode
 		}
 	}
 
-	resp, err := c.makeJsonHTTPRequest(http.MethodPost, c.baseURL+"/translate", reqBody)
+	resp, err := c.makeJsonHTTPRequest(ctx, http.MethodPost, c.baseURL+EndpointTranslate, reqBody)
 	if err != nil {
 		return nil, err
 	}
@@ -144,6 +152,90 @@ func (c *Client) Translate(input string, sourceLanguageCode, targetLanguageCode
 	}, nil
 }
 
+// translateModel returns the capabilities of the translation model in params. Models
+// missing from the registry are assumed to accept the input of the default model.
+func translateModel(params *TranslateParams) (ModelInfo, error) {
+	var id string
+	if params != nil {
+		id = modelID(params.Model)
+	}
+	model, ok, err := resolveModel(EndpointTranslate, id)
+	if err != nil || ok {
+		return model, err
+	}
+	defaultModel, _ := lookupModel(EndpointTranslate, defaultModels[EndpointTranslate])
+	return ModelInfo{ID: id, Endpoint: EndpointTranslate, MaxInputLength: defaultModel.MaxInputLength}, nil
+}
+
+// TranslateLongOptions contains optional settings for TranslateLong.
+type TranslateLongOptions struct {
+	MaxChunkLength int // Maximum length of each request; defaults to the model's input limit
+	Concurrency    int // Maximum number of concurrent requests; defaults to 4
+}
+
+// defaultTranslateConcurrency is the number of chunks TranslateLong translates at once by default.
+const defaultTranslateConcurrency = 4
+
+// TranslateLong translates text of any length by splitting it into chunks that fit the
+// model's input limit and translating them concurrently.
+//
+// Chunks are made of whole sentences, recognising the danda and other Indic sentence
+// terminators, and never span a line break, so that paragraphs and line breaks are
+// preserved in the reassembled translation. The response carries the request ID and
+// detected source language of the first chunk.
+func (c *Client) TranslateLong(input string, sourceLanguageCode, targetLanguageCode Language, params *TranslateParams, opts *TranslateLongOptions) (*TranslationResponse, error) {
+	return c.TranslateLongWithContext(context.Background(), input, sourceLanguageCode, targetLanguageCode, params, opts)
+}
+
+// TranslateLongWithContext is like TranslateLong but uses ctx to control cancellation and deadlines.
+func (c *Client) TranslateLongWithContext(ctx context.Context, input string, sourceLanguageCode, targetLanguageCode Language, params *TranslateParams, opts *TranslateLongOptions) (*TranslationResponse, error) {
+	model, err := translateModel(params)
+	if err != nil {
+		return nil, err
+	}
+	maxLength := model.MaxInputLength
+	concurrency := defaultTranslateConcurrency
+	if opts != nil {
+		if opts.MaxChunkLength > 0 {
+			maxLength = min(opts.MaxChunkLength, maxLength)
+		}
+		if opts.Concurrency > 0 {
+			concurrency = opts.Concurrency
+		}
+	}
+
+	leading, chunks := chunkText(input, maxLength, characterCount)
+	if len(chunks) == 0 {
+		return &TranslationResponse{TranslatedText: input, SourceLanguage: sourceLanguageCode}, nil
+	}
+
+	responses := make([]*TranslationResponse, len(chunks))
+	err = forEachConcurrently(ctx, len(chunks), concurrency, func(ctx context.Context, i int) error {
+		response, err := c.TranslateWithContext(ctx, chunks[i].text, sourceLanguageCode, targetLanguageCode, params)
+		if err != nil {
+			return fmt.Errorf("failed to translate chunk %d of %d: %w", i+1, len(chunks), err)
+		}
+		responses[i] = response
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var translated strings.Builder
+	translated.WriteString(leading)
+	for i, response := range responses {
+		translated.WriteString(response.TranslatedText)
+		translated.WriteString(chunks[i].space)
+	}
+
+	return &TranslationResponse{
+		RequestId:      responses[0].RequestId,
+		TranslatedText: translated.String(),
+		SourceLanguage: responses[0].SourceLanguage,
+	}, nil
+}
+
 // LanguageIdentification represents the result of language identification.
 type LanguageIdentificationResponse struct {
 	RequestId string
@@ -153,10 +245,20 @@ type LanguageIdentificationResponse struct {
 
 // IdentifyLanguage identifies the language (e.g., en-IN, hi-IN) and script (e.g., Latin, Devanagari) of the input text, supporting multiple languages.
 func (c *Client) IdentifyLanguage(input string) (*LanguageIdentificationResponse, error) {
+	return c.IdentifyLanguageWithContext(context.Background(), input)
+}
+
+// IdentifyLanguageWithContext is like IdentifyLanguage but uses ctx to control cancellation and deadlines.
+func (c *Client) IdentifyLanguageWithContext(ctx context.Context, input string) (*LanguageIdentificationResponse, error) {
+	model, _ := lookupModel(EndpointTextLID, "")
+	if err := model.validateInput(input); err != nil {
+		return nil, err
+	}
+
 	var payload = map[string]string{
 		"input": input,
 	}
-	resp, err := c.makeJsonHTTPRequest(http.MethodPost, c.baseURL+"/text-lid", payload)
+	resp, err := c.makeJsonHTTPRequest(ctx, http.MethodPost, c.baseURL+EndpointTextLID, payload)
 	if err != nil {
 		return nil, err
 	}
@@ -216,11 +318,20 @@ type TransliterateParams struct {
 
 // Transliterate converts text from one script to another while preserving the original pronunciation.
 func (c *Client) Transliterate(input string, sourceLanguage Language, targetLanguage Language, params *TransliterateParams) (*TransliterationResponse, error) {
-	if l := len(input); l > 1000 {
-		return nil, &ErrInputTooLong{
-			InputLength: l,
-			MaxLength:   1000,
-		}
+	return c.TransliterateWithContext(context.Background(), input, sourceLanguage, targetLanguage, params)
+}
+
+// TransliterateWithContext is like Transliterate but uses ctx to control cancellation and deadlines.
+func (c *Client) TransliterateWithContext(ctx context.Context, input string, sourceLanguage Language, targetLanguage Language, params *TransliterateParams) (*TransliterationResponse, error) {
+	model, _ := lookupModel(EndpointTransliterate, "")
+	if err := model.validateInput(input); err != nil {
+		return nil, err
+	}
+	if err := model.validateLanguage("source_language_code", sourceLanguage); err != nil {
+		return nil, err
+	}
+	if err := model.validateLanguage("target_language_code", targetLanguage); err != nil {
+		return nil, err
 	}
 
 	var payload = map[string]any{
@@ -242,7 +353,7 @@ func (c *Client) Transliterate(input string, sourceLanguage Language, targetLang
 		}
 	}
 
-	resp, err := c.makeJsonHTTPRequest(http.MethodPost, c.baseURL+"/transliterate", payload)
+	resp, err := c.makeJsonHTTPRequest(ctx, http.MethodPost, c.baseURL+EndpointTransliterate, payload)
 	if err != nil {
 		return nil, err
 	}
@@ -270,13 +381,3 @@ func (c *Client) Transliterate(input string, sourceLanguage Language, targetLang
 		SourceLanguage:     mapLanguageCodeToLanguage(response.SourceLanguage),
 	}, nil
 }
-
-// ErrInputTooLong is returned when the input length is greater than or equal to 1000 characters.
-type ErrInputTooLong struct {
-	InputLength int
-	MaxLength   int
-}
-
-func (e *ErrInputTooLong) Error() string {
-	return fmt.Sprintf("input length must be less than %d characters, got %d", e.MaxLength, e.InputLength)
-}
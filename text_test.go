@@ -1,6 +1,11 @@
 package sarvam
 
 import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
 	"testing"
 )
 
@@ -113,3 +118,91 @@ func TestTranslationString(t *testing.T) {
 		t.Errorf("Expected String() to return %s, got %s", expected, translation.String())
 	}
 }
+
+func TestTranslateLong(t *testing.T) {
+	var mu sync.Mutex
+	var inputs []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req map[string]any
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Errorf("Failed to decode request: %v", err)
+		}
+		input := req["input"].(string)
+		mu.Lock()
+		inputs = append(inputs, input)
+		mu.Unlock()
+		json.NewEncoder(w).Encode(map[string]string{
+			"request_id":           "req",
+			"translated_text":      strings.ToUpper(input),
+			"source_language_code": "en-IN",
+		})
+	}))
+	defer server.Close()
+
+	client := NewClient("test-key", WithBaseURL(server.URL))
+	input := "First sentence. Second sentence.\n\nThird paragraph here."
+	response, err := client.TranslateLong(input, LanguageEnglish, LanguageHindi, nil, &TranslateLongOptions{MaxChunkLength: 24, Concurrency: 2})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	expected := "FIRST SENTENCE. SECOND SENTENCE.\n\nTHIRD PARAGRAPH HERE."
+	if response.TranslatedText != expected {
+		t.Errorf("Expected %q, got %q", expected, response.TranslatedText)
+	}
+	if len(inputs) != 3 {
+		t.Errorf("Expected 3 requests, got %d", len(inputs))
+	}
+	if response.SourceLanguage != LanguageEnglish {
+		t.Errorf("Expected source language %v, got %v", LanguageEnglish, response.SourceLanguage)
+	}
+}
+
+func TestTranslateLongError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer server.Close()
+
+	client := NewClient("test-key", WithBaseURL(server.URL))
+	_, err := client.TranslateLong(strings.Repeat("Sentence. ", 10), LanguageEnglish, LanguageHindi, nil, &TranslateLongOptions{MaxChunkLength: 20})
+	if err == nil || !strings.Contains(err.Error(), "failed to translate chunk") {
+		t.Errorf("Expected chunk error, got %v", err)
+	}
+}
+
+func TestInputLengthCountsCharacters(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"request_id":"req","translated_text":"ok","source_language_code":"hi-IN"}`))
+	}))
+	defer server.Close()
+	client := NewClient("test-key", WithBaseURL(server.URL))
+
+	// 700 Devanagari characters take 2100 bytes but are within the 1000 character limit.
+	input := strings.Repeat("क", 700)
+	model := TranslationModelMayuraV1
+	if _, err := client.Translate(input, LanguageHindi, LanguageEnglish, &TranslateParams{Model: &model}); err != nil {
+		t.Errorf("Expected no error for 700 characters, got %v", err)
+	}
+
+	_, err := client.Transliterate(strings.Repeat("क", 1001), LanguageHindi, LanguageEnglish, nil)
+	inputErr, ok := err.(*ErrInputTooLong)
+	if !ok {
+		t.Fatalf("Expected *ErrInputTooLong, got %v", err)
+	}
+	if inputErr.InputLength != 1001 || inputErr.InputBytes != 3003 || inputErr.MaxLength != 1000 {
+		t.Errorf("Unexpected error fields: %+v", inputErr)
+	}
+	expected := "input length must be at most 1000 characters, got 1001 characters (3003 bytes)"
+	if inputErr.Error() != expected {
+		t.Errorf("Expected %q, got %q", expected, inputErr.Error())
+	}
+}
+
+func TestIdentifyLanguageInputLengthValidation(t *testing.T) {
+	client := NewClient("test-key")
+	_, err := client.IdentifyLanguage(strings.Repeat("a", 1001))
+	if _, ok := err.(*ErrInputTooLong); !ok {
+		t.Errorf("Expected *ErrInputTooLong, got %v", err)
+	}
+}
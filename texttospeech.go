@@ -1,10 +1,17 @@
 package sarvam
 
 import (
+	"context"
 	"encoding/base64"
 	"encoding/json"
+	"fmt"
+	"io"
 	"net/http"
 	"os"
+	"strings"
+	"time"
+
+	"code.abhai.dev/sarvam/audio"
 )
 
 // TextToSpeechResponse represents the result of a text-to-speech operation.
@@ -13,17 +20,103 @@ type TextToSpeechResponse struct {
 	Audios    []string
 }
 
+// Bytes returns the audio as a single file. If every chunk is a WAV file, they are merged
+// into one WAV file with correct headers; otherwise the chunks are concatenated as is.
 func (t *TextToSpeechResponse) Bytes() ([]byte, error) {
-	return convertAndConcatBase64ToBytes(t.Audios)
+	chunks, err := decodeBase64Chunks(t.Audios)
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case len(chunks) == 0:
+		return nil, nil
+	case len(chunks) == 1:
+		return chunks[0], nil
+	case !t.mergeWAV():
+		return concatChunks(chunks), nil
+	}
+	return audio.MergeWAV(chunks...)
+}
+
+// mergeWAV reports whether the chunks are WAV files to be merged into one, rather than
+// concatenated as is.
+func (t *TextToSpeechResponse) mergeWAV() bool {
+	if len(t.Audios) < 2 {
+		return false
+	}
+	for _, chunk := range t.Audios {
+		if !isBase64WAV(chunk) {
+			return false
+		}
+	}
+	return true
+}
+
+// WAV returns the audio parsed as a single WAV. It fails if the chunks are not WAV files
+// or do not share the same format.
+func (t *TextToSpeechResponse) WAV() (*audio.WAV, error) {
+	chunks, err := decodeBase64Chunks(t.Audios)
+	if err != nil {
+		return nil, err
+	}
+
+	wavs := make([]*audio.WAV, len(chunks))
+	for i, chunk := range chunks {
+		if wavs[i], err = audio.ParseWAV(chunk); err != nil {
+			return nil, err
+		}
+	}
+	return audio.Concat(wavs...)
+}
+
+// PCM returns the raw samples of the audio without WAV headers, along with their format.
+func (t *TextToSpeechResponse) PCM() ([]byte, audio.Format, error) {
+	wav, err := t.WAV()
+	if err != nil {
+		return nil, audio.Format{}, err
+	}
+	return wav.Data, wav.Format, nil
+}
+
+// Reader returns a reader producing the same audio as Bytes, decoding the chunks one at a
+// time as it is read instead of holding the whole file in memory.
+func (t *TextToSpeechResponse) Reader() io.Reader {
+	if !t.mergeWAV() {
+		readers := make([]io.Reader, len(t.Audios))
+		for i, chunk := range t.Audios {
+			readers[i] = base64.NewDecoder(base64.StdEncoding, strings.NewReader(chunk))
+		}
+		return io.MultiReader(readers...)
+	}
+
+	sources := make([]audio.Source, len(t.Audios))
+	for i, chunk := range t.Audios {
+		sources[i] = audio.Source{
+			Reader: func() io.Reader {
+				return base64.NewDecoder(base64.StdEncoding, strings.NewReader(chunk))
+			},
+			Size: int64(base64DecodedLen(chunk)),
+		}
+	}
+	return audio.NewMergeReader(sources...)
+}
+
+// WriteTo implements io.WriterTo, writing the audio as Bytes would return it.
+func (t *TextToSpeechResponse) WriteTo(w io.Writer) (int64, error) {
+	return io.Copy(w, t.Reader())
 }
 
 // Save saves the text-to-speech data as a WAV file.
 func (t *TextToSpeechResponse) Save(filename string) error {
-	data, err := t.Bytes()
+	file, err := os.Create(filename)
 	if err != nil {
 		return err
 	}
-	return os.WriteFile(filename, data, 0644)
+	if _, err := t.WriteTo(file); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
 }
 
 // TextToSpeechParams contains all parameters for text-to-speech conversion.
@@ -49,7 +142,97 @@ var (
 
 // TextToSpeech converts text to speech in the specified language.
 func (c *Client) TextToSpeech(text string, targetLanguage Language, params TextToSpeechParams) (*TextToSpeechResponse, error) {
-	var payload = map[string]any{
+	return c.TextToSpeechWithContext(context.Background(), text, targetLanguage, params)
+}
+
+// TextToSpeechWithContext is like TextToSpeech but uses ctx to control cancellation and deadlines.
+func (c *Client) TextToSpeechWithContext(ctx context.Context, text string, targetLanguage Language, params TextToSpeechParams) (*TextToSpeechResponse, error) {
+	if err := validateTextToSpeechRequest(EndpointTextToSpeech, text, targetLanguage, params); err != nil {
+		return nil, err
+	}
+
+	payload := newTextToSpeechRequest(text, targetLanguage, params)
+
+	resp, err := c.makeJsonHTTPRequest(ctx, http.MethodPost, c.baseURL+EndpointTextToSpeech, payload)
+	if err != nil {
+		return nil, err
+	}
+	if resp.Body != nil {
+		defer resp.Body.Close()
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, parseAPIError(resp)
+	}
+
+	type textToSpeechResponse struct {
+		RequestId string   `json:"request_id"`
+		Audios    []string `json:"audios"`
+	}
+
+	var response textToSpeechResponse
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return nil, err
+	}
+
+	return &TextToSpeechResponse{
+		RequestId: response.RequestId,
+		Audios:    response.Audios,
+	}, nil
+}
+
+// TextToSpeechStream converts text to speech using the streaming endpoint, returning the
+// audio as it is synthesized so that playback can start before synthesis finishes.
+// The caller must close the returned reader.
+func (c *Client) TextToSpeechStream(text string, targetLanguage Language, params TextToSpeechParams) (io.ReadCloser, error) {
+	return c.TextToSpeechStreamWithContext(context.Background(), text, targetLanguage, params)
+}
+
+// TextToSpeechStreamWithContext is like TextToSpeechStream but uses ctx to control cancellation and deadlines.
+// Canceling ctx also aborts reading the audio.
+func (c *Client) TextToSpeechStreamWithContext(ctx context.Context, text string, targetLanguage Language, params TextToSpeechParams) (io.ReadCloser, error) {
+	if err := validateTextToSpeechRequest(EndpointTextToSpeechStream, text, targetLanguage, params); err != nil {
+		return nil, err
+	}
+
+	payload := newTextToSpeechRequest(text, targetLanguage, params)
+	resp, err := c.makeJsonHTTPRequest(ctx, http.MethodPost, c.baseURL+EndpointTextToSpeechStream, payload)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		defer resp.Body.Close()
+		return nil, parseAPIError(resp)
+	}
+	return resp.Body, nil
+}
+
+// validateTextToSpeechRequest checks a request to a text-to-speech endpoint against the
+// capabilities of the model in params.
+func validateTextToSpeechRequest(endpoint, text string, targetLanguage Language, params TextToSpeechParams) error {
+	model, ok, err := textToSpeechModel(endpoint, params)
+	if err != nil || !ok {
+		return err
+	}
+	if err := model.validateInput(text); err != nil {
+		return err
+	}
+	if err := model.validateLanguage("target_language_code", targetLanguage); err != nil {
+		return err
+	}
+	if params.Speaker != nil {
+		if err := model.validateSpeaker(*params.Speaker); err != nil {
+			return err
+		}
+	}
+	if params.SpeechSampleRate != nil {
+		return model.validateSampleRate("speech_sample_rate", int(*params.SpeechSampleRate))
+	}
+	return nil
+}
+
+// newTextToSpeechRequest builds the request body of the text-to-speech endpoints.
+func newTextToSpeechRequest(text string, targetLanguage Language, params TextToSpeechParams) map[string]any {
+	payload := map[string]any{
 		"text":                 text,
 		"target_language_code": targetLanguage,
 	}
@@ -75,45 +258,128 @@ func (c *Client) TextToSpeech(text string, targetLanguage Language, params TextT
 	if params.Model != nil {
 		payload["model"] = *params.Model
 	}
+	return payload
+}
+
+// textToSpeechModel returns the capabilities of the text-to-speech model in params, and
+// whether they are known.
+func textToSpeechModel(endpoint string, params TextToSpeechParams) (ModelInfo, bool, error) {
+	return resolveModel(endpoint, modelID(params.Model))
+}
+
+// SynthesizeLongOptions contains optional settings for SynthesizeLong.
+type SynthesizeLongOptions struct {
+	MaxSegmentLength int           // Maximum length of each request; defaults to the model's input limit
+	Concurrency      int           // Maximum number of concurrent requests; defaults to 4
+	Silence          time.Duration // Silence inserted between segments
+}
+
+// defaultSynthesizeConcurrency is the number of segments SynthesizeLong synthesizes at once by default.
+const defaultSynthesizeConcurrency = 4
+
+// SynthesizeLong converts text of any length to speech by splitting it into segments that
+// fit the model's input limit, synthesizing them concurrently and merging the audio in order.
+//
+// Segments are made of whole sentences where possible, recognising the danda and the other
+// sentence terminators of the supported scripts, and fall back to clause and word boundaries
+// for longer sentences.
+func (c *Client) SynthesizeLong(text string, targetLanguage Language, params TextToSpeechParams, opts *SynthesizeLongOptions) (*audio.WAV, error) {
+	return c.SynthesizeLongWithContext(context.Background(), text, targetLanguage, params, opts)
+}
 
-	resp, err := c.makeJsonHTTPRequest(http.MethodPost, c.baseURL+"/text-to-speech", payload)
+// SynthesizeLongWithContext is like SynthesizeLong but uses ctx to control cancellation and deadlines.
+func (c *Client) SynthesizeLongWithContext(ctx context.Context, text string, targetLanguage Language, params TextToSpeechParams, opts *SynthesizeLongOptions) (*audio.WAV, error) {
+	if opts == nil {
+		opts = &SynthesizeLongOptions{}
+	}
+	model, ok, err := textToSpeechModel(EndpointTextToSpeech, params)
 	if err != nil {
 		return nil, err
 	}
-	if resp.Body != nil {
-		defer resp.Body.Close()
+	maxLength := model.MaxInputLength
+	if opts.MaxSegmentLength > 0 && (!ok || opts.MaxSegmentLength < maxLength) {
+		maxLength, ok = opts.MaxSegmentLength, true
 	}
-	if resp.StatusCode != http.StatusOK {
-		return nil, parseAPIError(resp)
+	if !ok {
+		return nil, fmt.Errorf("input limit of model %q is unknown, set MaxSegmentLength", *params.Model)
+	}
+	concurrency := opts.Concurrency
+	if concurrency <= 0 {
+		concurrency = defaultSynthesizeConcurrency
 	}
 
-	type textToSpeechResponse struct {
-		RequestId string   `json:"request_id"`
-		Audios    []string `json:"audios"`
+	_, segments := chunkText(text, maxLength, characterCount)
+	if len(segments) == 0 {
+		return nil, fmt.Errorf("text cannot be empty")
 	}
 
-	var response textToSpeechResponse
-	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+	wavs := make([]*audio.WAV, len(segments))
+	err = forEachConcurrently(ctx, len(segments), concurrency, func(ctx context.Context, i int) error {
+		response, err := c.TextToSpeechWithContext(ctx, segments[i].text, targetLanguage, params)
+		if err != nil {
+			return fmt.Errorf("failed to synthesize segment %d of %d: %w", i+1, len(segments), err)
+		}
+		if wavs[i], err = response.WAV(); err != nil {
+			return fmt.Errorf("failed to decode segment %d of %d: %w", i+1, len(segments), err)
+		}
+		return nil
+	})
+	if err != nil {
 		return nil, err
 	}
 
-	return &TextToSpeechResponse{
-		RequestId: response.RequestId,
-		Audios:    response.Audios,
-	}, nil
+	if opts.Silence > 0 {
+		silence := audio.Silence(wavs[0].Format, opts.Silence)
+		spaced := make([]*audio.WAV, 0, 2*len(wavs)-1)
+		for i, wav := range wavs {
+			if i > 0 {
+				spaced = append(spaced, silence)
+			}
+			spaced = append(spaced, wav)
+		}
+		wavs = spaced
+	}
+	return audio.Concat(wavs...)
 }
 
-// convertAndConcatBase64ToBytes converts multiple base64-encoded audio chunks to a single byte array.
-func convertAndConcatBase64ToBytes(base64Strs []string) ([]byte, error) {
-	var data []byte
-	for _, base64Str := range base64Strs {
+// decodeBase64Chunks decodes base64-encoded audio chunks.
+func decodeBase64Chunks(base64Strs []string) ([][]byte, error) {
+	chunks := make([][]byte, len(base64Strs))
+	for i, base64Str := range base64Strs {
 		decodedBytes, err := convertBase64ToBytes(base64Str)
 		if err != nil {
 			return nil, err
 		}
-		data = append(data, decodedBytes...)
+		chunks[i] = decodedBytes
+	}
+	return chunks, nil
+}
+
+// isBase64WAV reports whether the base64-encoded chunk starts with a WAV header.
+func isBase64WAV(chunk string) bool {
+	if len(chunk) < 16 {
+		return false
+	}
+	header, err := base64.StdEncoding.DecodeString(chunk[:16])
+	return err == nil && string(header[0:4]) == "RIFF" && string(header[8:12]) == "WAVE"
+}
+
+// base64DecodedLen returns the exact number of bytes the padded base64 string decodes to.
+func base64DecodedLen(s string) int {
+	n := len(s) / 4 * 3
+	for i := len(s) - 1; i >= 0 && i >= len(s)-2 && s[i] == '='; i-- {
+		n--
+	}
+	return n
+}
+
+// concatChunks joins audio chunks byte for byte.
+func concatChunks(chunks [][]byte) []byte {
+	var data []byte
+	for _, chunk := range chunks {
+		data = append(data, chunk...)
 	}
-	return data, nil
+	return data
 }
 
 // convertBase64ToBytes converts a single base64-encoded string to bytes.
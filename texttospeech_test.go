@@ -0,0 +1,178 @@
+package sarvam
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"code.abhai.dev/sarvam/audio"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func encodeWAV(format audio.Format, data []byte) string {
+	return base64.StdEncoding.EncodeToString((&audio.WAV{Format: format, Data: data}).Bytes())
+}
+
+var testTTSFormat = audio.Format{AudioFormat: audio.FormatPCM, Channels: 1, SampleRate: 22050, BitsPerSample: 16}
+
+func TestTextToSpeechResponseBytesMergesWAV(t *testing.T) {
+	response := &TextToSpeechResponse{Audios: []string{
+		encodeWAV(testTTSFormat, []byte{1, 2}),
+		encodeWAV(testTTSFormat, []byte{3, 4}),
+	}}
+
+	data, err := response.Bytes()
+	require.NoError(t, err)
+	wav, err := audio.ParseWAV(data)
+	require.NoError(t, err)
+	assert.Equal(t, []byte{1, 2, 3, 4}, wav.Data)
+	assert.Len(t, data, 44+4)
+
+	pcm, format, err := response.PCM()
+	require.NoError(t, err)
+	assert.Equal(t, []byte{1, 2, 3, 4}, pcm)
+	assert.Equal(t, testTTSFormat, format)
+}
+
+func TestTextToSpeechResponseBytesMismatchedFormats(t *testing.T) {
+	other := testTTSFormat
+	other.SampleRate = 8000
+	response := &TextToSpeechResponse{Audios: []string{
+		encodeWAV(testTTSFormat, []byte{1, 2}),
+		encodeWAV(other, []byte{3, 4}),
+	}}
+
+	_, err := response.Bytes()
+	var mismatch *audio.FormatMismatchError
+	assert.ErrorAs(t, err, &mismatch)
+}
+
+func TestTextToSpeechResponseBytesNonWAV(t *testing.T) {
+	response := &TextToSpeechResponse{Audios: []string{
+		base64.StdEncoding.EncodeToString([]byte("ID3frame1")),
+		base64.StdEncoding.EncodeToString([]byte("frame2")),
+	}}
+
+	data, err := response.Bytes()
+	require.NoError(t, err)
+	assert.Equal(t, "ID3frame1frame2", string(data))
+
+	empty, err := (&TextToSpeechResponse{}).Bytes()
+	assert.NoError(t, err)
+	assert.Empty(t, empty)
+}
+
+func TestTextToSpeechResponseReader(t *testing.T) {
+	other := testTTSFormat
+	other.SampleRate = 8000
+	responses := []*TextToSpeechResponse{
+		{Audios: []string{encodeWAV(testTTSFormat, []byte{1, 2}), encodeWAV(testTTSFormat, []byte{3, 4, 5, 6})}},
+		{Audios: []string{encodeWAV(testTTSFormat, []byte{1, 2, 3})}},
+		{Audios: []string{base64.StdEncoding.EncodeToString([]byte("ID3frame1")), base64.StdEncoding.EncodeToString([]byte("frame2"))}},
+		// Chunks of mixed formats are concatenated, whichever comes first.
+		{Audios: []string{encodeWAV(testTTSFormat, []byte{1, 2}), base64.StdEncoding.EncodeToString([]byte("frame2"))}},
+		{Audios: []string{base64.StdEncoding.EncodeToString([]byte("frame1")), encodeWAV(testTTSFormat, []byte{1, 2})}},
+		{},
+	}
+	for _, response := range responses {
+		want, err := response.Bytes()
+		require.NoError(t, err)
+
+		var buf bytes.Buffer
+		n, err := response.WriteTo(&buf)
+		require.NoError(t, err)
+		assert.Equal(t, int64(len(want)), n)
+		assert.Equal(t, want, buf.Bytes())
+	}
+
+	mismatched := &TextToSpeechResponse{Audios: []string{encodeWAV(testTTSFormat, nil), encodeWAV(other, nil)}}
+	_, err := io.ReadAll(mismatched.Reader())
+	var mismatch *audio.FormatMismatchError
+	assert.ErrorAs(t, err, &mismatch)
+}
+
+func TestTextToSpeechStream(t *testing.T) {
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, EndpointTextToSpeechStream, r.URL.Path)
+		var req map[string]any
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		assert.Equal(t, "hello", req["text"])
+		assert.Equal(t, "anushka", req["speaker"])
+
+		w.Header().Set("Content-Type", "audio/mpeg")
+		w.Write([]byte("chunk1"))
+		w.(http.Flusher).Flush()
+		w.Write([]byte("chunk2"))
+	}))
+	defer s.Close()
+
+	client := NewClient("test", WithBaseURL(s.URL))
+	stream, err := client.TextToSpeechStream("hello", LanguageHindi, TextToSpeechParams{Speaker: &SpeakerAnushka})
+	require.NoError(t, err)
+	defer stream.Close()
+
+	data, err := io.ReadAll(stream)
+	require.NoError(t, err)
+	assert.Equal(t, "chunk1chunk2", string(data))
+}
+
+func TestTextToSpeechInputLengthValidation(t *testing.T) {
+	client := NewClient("test")
+	_, err := client.TextToSpeech(strings.Repeat("क", 1501), LanguageHindi, TextToSpeechParams{})
+	var inputErr *ErrInputTooLong
+	require.ErrorAs(t, err, &inputErr)
+	assert.Equal(t, 1500, inputErr.MaxLength)
+}
+
+// newTTSServer returns a server that synthesizes each text as one 16-bit sample per
+// character, holding the character's position in the alphabet.
+func newTTSServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req map[string]any
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		var data []byte
+		for _, c := range req["text"].(string) {
+			if c != ' ' && c != '.' {
+				data = append(data, byte(c-'a'+1), 0)
+			}
+		}
+		json.NewEncoder(w).Encode(map[string]any{
+			"request_id": "req",
+			"audios":     []string{encodeWAV(testTTSFormat, data)},
+		})
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+func TestSynthesizeLong(t *testing.T) {
+	client := NewClient("test", WithBaseURL(newTTSServer(t).URL))
+
+	wav, err := client.SynthesizeLong("ab. cd. ef.", LanguageEnglish, TextToSpeechParams{}, &SynthesizeLongOptions{
+		MaxSegmentLength: 4,
+		Concurrency:      3,
+		Silence:          time.Second / 22050,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, testTTSFormat, wav.Format)
+	assert.Equal(t, []byte{1, 0, 2, 0, 0, 0, 3, 0, 4, 0, 0, 0, 5, 0, 6, 0}, wav.Data)
+}
+
+func TestSynthesizeLongErrors(t *testing.T) {
+	client := NewClient("test", WithBaseURL(newTTSServer(t).URL))
+
+	_, err := client.SynthesizeLong("   ", LanguageEnglish, TextToSpeechParams{}, nil)
+	assert.ErrorContains(t, err, "text cannot be empty")
+
+	model := TextToSpeechModel("bulbul:v9")
+	_, err = client.SynthesizeLong("ab.", LanguageEnglish, TextToSpeechParams{Model: &model}, nil)
+	assert.ErrorContains(t, err, "set MaxSegmentLength")
+}
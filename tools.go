@@ -0,0 +1,228 @@
+package sarvam
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// Tool describes a function that the model may call.
+type Tool struct {
+	Type     string             `json:"type"`
+	Function FunctionDefinition `json:"function"`
+}
+
+// FunctionDefinition describes the name and arguments of a callable function.
+type FunctionDefinition struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	Parameters  any    `json:"parameters,omitempty"` // JSON schema of the arguments object
+}
+
+// NewFunctionTool creates a tool for a function whose arguments are described by the
+// JSON schema in parameters.
+func NewFunctionTool(name, description string, parameters any) Tool {
+	return Tool{
+		Type: "function",
+		Function: FunctionDefinition{
+			Name:        name,
+			Description: description,
+			Parameters:  parameters,
+		},
+	}
+}
+
+// ToolCall represents a call to a tool requested by the model.
+type ToolCall struct {
+	ID       string       `json:"id"`
+	Type     string       `json:"type"`
+	Function FunctionCall `json:"function"`
+}
+
+// FunctionCall holds the name of the called function and its arguments encoded as JSON.
+type FunctionCall struct {
+	Name      string `json:"name,omitempty"`
+	Arguments string `json:"arguments,omitempty"`
+}
+
+// ToolChoice controls whether the model calls tools, and which.
+type ToolChoice struct {
+	mode     string
+	function string
+}
+
+var (
+	// ToolChoiceAuto lets the model decide whether to call tools.
+	ToolChoiceAuto = ToolChoice{mode: "auto"}
+	// ToolChoiceNone prevents the model from calling tools.
+	ToolChoiceNone = ToolChoice{mode: "none"}
+	// ToolChoiceRequired forces the model to call at least one tool.
+	ToolChoiceRequired = ToolChoice{mode: "required"}
+)
+
+// ToolChoiceFunction forces the model to call the function with the given name.
+func ToolChoiceFunction(name string) ToolChoice {
+	return ToolChoice{function: name}
+}
+
+// MarshalJSON implements json.Marshaler for ToolChoice.
+func (t ToolChoice) MarshalJSON() ([]byte, error) {
+	if t.function != "" {
+		type function struct {
+			Name string `json:"name"`
+		}
+		return json.Marshal(struct {
+			Type     string   `json:"type"`
+			Function function `json:"function"`
+		}{Type: "function", Function: function{Name: t.function}})
+	}
+	return json.Marshal(t.mode)
+}
+
+// UnmarshalJSON implements json.Unmarshaler for ToolChoice.
+func (t *ToolChoice) UnmarshalJSON(data []byte) error {
+	var mode string
+	if err := json.Unmarshal(data, &mode); err == nil {
+		*t = ToolChoice{mode: mode}
+		return nil
+	}
+	var function struct {
+		Function struct {
+			Name string `json:"name"`
+		} `json:"function"`
+	}
+	if err := json.Unmarshal(data, &function); err != nil {
+		return err
+	}
+	*t = ToolChoiceFunction(function.Function.Name)
+	return nil
+}
+
+// ToolFunc handles a call to a registered tool. It receives the arguments chosen by the
+// model as JSON and returns the content sent back to the model.
+type ToolFunc func(ctx context.Context, arguments json.RawMessage) (string, error)
+
+// ErrToolLoopLimit is returned by RunTools when the model keeps calling tools after the
+// maximum number of rounds.
+var ErrToolLoopLimit = errors.New("tool call limit reached without a final answer")
+
+// defaultMaxToolRounds is the number of rounds of tool calls RunTools allows by default.
+const defaultMaxToolRounds = 10
+
+// ToolRegistry maps tool names to the Go functions that handle them.
+type ToolRegistry struct {
+	// MaxRounds limits how many rounds of tool calls RunTools performs before giving up.
+	// If zero, a default of 10 is used.
+	MaxRounds int
+
+	tools []Tool
+	funcs map[string]ToolFunc
+}
+
+// NewToolRegistry creates an empty tool registry. The zero value is also an empty registry
+// ready to use.
+func NewToolRegistry() *ToolRegistry {
+	return &ToolRegistry{funcs: make(map[string]ToolFunc)}
+}
+
+// Register adds a function tool handled by fn. Registering a name twice replaces the
+// earlier tool.
+func (r *ToolRegistry) Register(name, description string, parameters any, fn ToolFunc) {
+	if _, ok := r.funcs[name]; ok {
+		for i, tool := range r.tools {
+			if tool.Function.Name == name {
+				r.tools = append(r.tools[:i], r.tools[i+1:]...)
+				break
+			}
+		}
+	}
+	if r.funcs == nil {
+		r.funcs = make(map[string]ToolFunc)
+	}
+	r.tools = append(r.tools, NewFunctionTool(name, description, parameters))
+	r.funcs[name] = fn
+}
+
+// Tools returns the registered tools, in registration order.
+func (r *ToolRegistry) Tools() []Tool {
+	return append([]Tool(nil), r.tools...)
+}
+
+// Call dispatches a tool call to its registered function.
+func (r *ToolRegistry) Call(ctx context.Context, call ToolCall) (string, error) {
+	fn, ok := r.funcs[call.Function.Name]
+	if !ok {
+		return "", fmt.Errorf("unknown tool %q", call.Function.Name)
+	}
+	arguments := json.RawMessage(call.Function.Arguments)
+	if len(arguments) == 0 {
+		arguments = json.RawMessage("{}")
+	}
+	return fn(ctx, arguments)
+}
+
+// RunTools creates chat completions with the tools in registry, dispatching every tool call
+// the model makes and sending the results back until it produces a final answer.
+// It returns the final response together with the conversation, including the tool calls,
+// tool results and final answer, appended to messages.
+//
+// Errors returned by tool functions are reported to the model as the tool's result rather
+// than ending the loop, so that the model can recover from them.
+func (c *Client) RunTools(messages []Message, model ChatCompletionModel, req *ChatCompletionParams, registry *ToolRegistry) (*ChatCompletionResponse, []Message, error) {
+	return c.RunToolsWithContext(context.Background(), messages, model, req, registry)
+}
+
+// RunToolsWithContext is like RunTools but uses ctx to control cancellation and deadlines.
+// The same ctx is passed to the tool functions.
+func (c *Client) RunToolsWithContext(ctx context.Context, messages []Message, model ChatCompletionModel, req *ChatCompletionParams, registry *ToolRegistry) (*ChatCompletionResponse, []Message, error) {
+	if registry == nil {
+		return nil, messages, errors.New("tool registry is nil")
+	}
+	var params ChatCompletionParams
+	if req != nil {
+		params = *req
+	}
+	params.Tools = registry.Tools()
+
+	maxRounds := registry.MaxRounds
+	if maxRounds <= 0 {
+		maxRounds = defaultMaxToolRounds
+	}
+
+	history := append([]Message(nil), messages...)
+	for round := 0; ; round++ {
+		if round > 0 && params.ToolChoice != nil && *params.ToolChoice != ToolChoiceNone {
+			// A forced tool choice would otherwise make the model call tools forever.
+			params.ToolChoice = Ptr(ToolChoiceAuto)
+		}
+
+		response, err := c.ChatCompletionWithContext(ctx, history, model, &params)
+		if err != nil {
+			return nil, history, err
+		}
+		if len(response.Choices) == 0 {
+			return response, history, nil
+		}
+
+		reply := response.Choices[0].Message
+		history = append(history, reply)
+		if len(reply.ToolCalls) == 0 {
+			return response, history, nil
+		}
+		if round+1 >= maxRounds {
+			return response, history, ErrToolLoopLimit
+		}
+
+		for _, call := range reply.ToolCalls {
+			content, err := registry.Call(ctx, call)
+			if err != nil {
+				if ctxErr := ctx.Err(); ctxErr != nil {
+					return nil, history, contextError(ctx, ctxErr)
+				}
+				content = fmt.Sprintf("error: %v", err)
+			}
+			history = append(history, NewToolMessage(call.ID, content))
+		}
+	}
+}
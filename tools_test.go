@@ -0,0 +1,172 @@
+package sarvam
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestToolChoiceMarshalJSON(t *testing.T) {
+	data, err := json.Marshal(ToolChoiceAuto)
+	require.NoError(t, err)
+	assert.JSONEq(t, `"auto"`, string(data))
+
+	data, err = json.Marshal(ToolChoiceFunction("get_weather"))
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"type":"function","function":{"name":"get_weather"}}`, string(data))
+
+	var choice ToolChoice
+	require.NoError(t, json.Unmarshal(data, &choice))
+	assert.Equal(t, ToolChoiceFunction("get_weather"), choice)
+	require.NoError(t, json.Unmarshal([]byte(`"none"`), &choice))
+	assert.Equal(t, ToolChoiceNone, choice)
+}
+
+func TestMessageToolCallsJSON(t *testing.T) {
+	var message Message
+	err := json.Unmarshal([]byte(`{"role":"assistant","content":null,"tool_calls":[{"id":"call_1","type":"function","function":{"name":"get_weather","arguments":"{\"city\":\"Kochi\"}"}}]}`), &message)
+	require.NoError(t, err)
+	require.Len(t, message.ToolCalls, 1)
+	assert.Equal(t, "get_weather", message.ToolCalls[0].Function.Name)
+
+	data, err := json.Marshal(NewToolMessage("call_1", "31°C"))
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"role":"tool","content":"31°C","tool_call_id":"call_1"}`, string(data))
+}
+
+func TestRunTools(t *testing.T) {
+	var requests []chatCompletionRequest
+	httpTestServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req chatCompletionRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		requests = append(requests, req)
+
+		if len(requests) == 1 {
+			w.Write([]byte(`{"id":"1","choices":[{"index":0,"finish_reason":"tool_calls","message":{"role":"assistant","content":"","tool_calls":[
+				{"id":"call_1","type":"function","function":{"name":"get_weather","arguments":"{\"city\":\"Kochi\"}"}},
+				{"id":"call_2","type":"function","function":{"name":"get_time","arguments":"{}"}}
+			]}}]}`))
+			return
+		}
+		w.Write([]byte(`{"id":"2","choices":[{"index":0,"finish_reason":"stop","message":{"role":"assistant","content":"It is 31°C in Kochi."}}]}`))
+	}))
+	defer httpTestServer.Close()
+
+	registry := NewToolRegistry()
+	registry.Register("get_weather", "Get the weather for a city", map[string]any{
+		"type":       "object",
+		"properties": map[string]any{"city": map[string]any{"type": "string"}},
+	}, func(ctx context.Context, arguments json.RawMessage) (string, error) {
+		var args struct {
+			City string `json:"city"`
+		}
+		if err := json.Unmarshal(arguments, &args); err != nil {
+			return "", err
+		}
+		return "31°C in " + args.City, nil
+	})
+	registry.Register("get_time", "Get the time", nil, func(ctx context.Context, arguments json.RawMessage) (string, error) {
+		return "", errors.New("clock unavailable")
+	})
+
+	client := NewClient("test", WithBaseURL(httpTestServer.URL))
+	response, history, err := client.RunTools([]Message{NewUserMessage("Weather in Kochi?")}, ChatCompletionModelSarvamM, &ChatCompletionParams{ToolChoice: Ptr(ToolChoiceRequired)}, registry)
+	require.NoError(t, err)
+	assert.Equal(t, "It is 31°C in Kochi.", response.GetFirstChoiceContent())
+
+	require.Len(t, requests, 2)
+	require.Len(t, requests[0].Tools, 2)
+	assert.Equal(t, "get_weather", requests[0].Tools[0].Function.Name)
+	assert.Equal(t, ToolChoiceRequired, *requests[0].ToolChoice)
+	assert.Equal(t, ToolChoiceAuto, *requests[1].ToolChoice)
+
+	require.Len(t, history, 5)
+	assert.Equal(t, NewToolMessage("call_1", "31°C in Kochi"), history[2])
+	assert.Equal(t, NewToolMessage("call_2", "error: clock unavailable"), history[3])
+	assert.Equal(t, string(MessageRoleAssistant), history[4].Role)
+}
+
+func TestRunToolsLimit(t *testing.T) {
+	httpTestServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"id":"1","choices":[{"index":0,"message":{"role":"assistant","content":"","tool_calls":[{"id":"call_1","type":"function","function":{"name":"noop","arguments":""}}]}}]}`))
+	}))
+	defer httpTestServer.Close()
+
+	registry := NewToolRegistry()
+	registry.MaxRounds = 2
+	calls := 0
+	registry.Register("noop", "", nil, func(ctx context.Context, arguments json.RawMessage) (string, error) {
+		calls++
+		assert.JSONEq(t, `{}`, string(arguments))
+		return "ok", nil
+	})
+
+	client := NewClient("test", WithBaseURL(httpTestServer.URL))
+	_, _, err := client.RunTools([]Message{NewUserMessage("loop")}, ChatCompletionModelSarvamM, nil, registry)
+	assert.ErrorIs(t, err, ErrToolLoopLimit)
+	assert.Equal(t, 1, calls)
+}
+
+func TestToolRegistryUnknownTool(t *testing.T) {
+	registry := NewToolRegistry()
+	_, err := registry.Call(context.Background(), ToolCall{Function: FunctionCall{Name: "missing"}})
+	assert.ErrorContains(t, err, `unknown tool "missing"`)
+}
+
+func TestToolRegistryRegisterReplaces(t *testing.T) {
+	registry := NewToolRegistry()
+	registry.Register("a", "first", nil, nil)
+	registry.Register("b", "", nil, nil)
+	registry.Register("a", "second", nil, nil)
+
+	tools := registry.Tools()
+	require.Len(t, tools, 2)
+	assert.Equal(t, "b", tools[0].Function.Name)
+	assert.Equal(t, "second", tools[1].Function.Description)
+}
+
+func TestToolRegistryZeroValue(t *testing.T) {
+	registry := &ToolRegistry{MaxRounds: 3}
+	registry.Register("echo", "", nil, func(ctx context.Context, arguments json.RawMessage) (string, error) {
+		return string(arguments), nil
+	})
+	result, err := registry.Call(context.Background(), ToolCall{Function: FunctionCall{Name: "echo", Arguments: `{"a":1}`}})
+	require.NoError(t, err)
+	assert.Equal(t, `{"a":1}`, result)
+	assert.Len(t, registry.Tools(), 1)
+}
+
+func TestRunToolsNilRegistry(t *testing.T) {
+	_, _, err := NewClient("test").RunTools([]Message{NewUserMessage("hi")}, ChatCompletionModelSarvamM, nil, nil)
+	assert.EqualError(t, err, "tool registry is nil")
+}
+
+func TestAccumulatorToolCalls(t *testing.T) {
+	var acc ChatCompletionAccumulator
+	require.NoError(t, acc.Add(&ChatCompletionChunk{Choices: []ChatCompletionChunkChoice{{Delta: ChatCompletionDelta{ToolCalls: []ToolCallDelta{
+		{Index: 0, ID: "call_1", Type: "function", Function: FunctionCall{Name: "get_weather", Arguments: `{"ci`}},
+	}}}}}))
+	require.NoError(t, acc.Add(&ChatCompletionChunk{Choices: []ChatCompletionChunkChoice{{Delta: ChatCompletionDelta{ToolCalls: []ToolCallDelta{
+		{Index: 0, Function: FunctionCall{Arguments: `ty":"Kochi"}`}},
+	}}}}}))
+
+	message := acc.Response().Choices[0].Message
+	require.Len(t, message.ToolCalls, 1)
+	assert.Equal(t, ToolCall{ID: "call_1", Type: "function", Function: FunctionCall{Name: "get_weather", Arguments: `{"city":"Kochi"}`}}, message.ToolCalls[0])
+}
+
+func TestAccumulatorInvalidToolCallIndex(t *testing.T) {
+	for _, index := range []int{-1, 1, 1 << 40} {
+		var acc ChatCompletionAccumulator
+		err := acc.Add(&ChatCompletionChunk{Choices: []ChatCompletionChunkChoice{{Delta: ChatCompletionDelta{ToolCalls: []ToolCallDelta{
+			{Index: index, ID: "call_1", Function: FunctionCall{Name: "get_weather"}},
+		}}}}})
+		assert.ErrorContains(t, err, "invalid tool call index", index)
+	}
+}
@@ -0,0 +1,158 @@
+package sarvam
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// characterCount returns the length of s in the units the API limits it by: Unicode code
+// points. Combining vowel signs count separately, so "कि" is two characters, but each
+// counts once rather than as its three UTF-8 bytes.
+func characterCount(s string) int {
+	return utf8.RuneCountInString(s)
+}
+
+// validateInputLength returns an *ErrInputTooLong error if input is longer than maxLength characters.
+func validateInputLength(input string, maxLength int) error {
+	if l := characterCount(input); l > maxLength {
+		return &ErrInputTooLong{
+			InputLength: l,
+			InputBytes:  len(input),
+			MaxLength:   maxLength,
+		}
+	}
+	return nil
+}
+
+// ErrInputTooLong is returned when the input is longer than the endpoint or model accepts.
+type ErrInputTooLong struct {
+	InputLength int // Length of the input in characters (Unicode code points)
+	InputBytes  int // Length of the input in bytes, as encoded in UTF-8
+	MaxLength   int // Maximum length in characters
+}
+
+func (e *ErrInputTooLong) Error() string {
+	return fmt.Sprintf("input length must be at most %d characters, got %d characters (%d bytes)", e.MaxLength, e.InputLength, e.InputBytes)
+}
+
+// Is reports whether target is ErrValidation, so that the error is classified like the
+// validation errors returned by the API.
+func (e *ErrInputTooLong) Is(target error) bool {
+	return target == ErrValidation
+}
+
+// UnsupportedError is returned, before a request is sent, when it names a model the endpoint
+// does not serve, or a language, speaker or sample rate its model does not support.
+type UnsupportedError struct {
+	Endpoint  string
+	Model     string   // Model the request uses; empty for endpoints without a choice of model
+	Parameter string   // Unsupported parameter, such as "model" or "speaker"
+	Value     string   // Unsupported value
+	Supported []string // Values the model supports, if the parameter is not the model
+}
+
+func (e *UnsupportedError) Error() string {
+	if e.Parameter == "model" {
+		return fmt.Sprintf("model %q is not supported by %s", e.Value, e.Endpoint)
+	}
+	subject := e.Endpoint
+	if e.Model != "" {
+		subject = fmt.Sprintf("model %q", e.Model)
+	}
+	return fmt.Sprintf("%s %q is not supported by %s; supported values are %s", e.Parameter, e.Value, subject, strings.Join(e.Supported, ", "))
+}
+
+// Is reports whether target is ErrValidation.
+func (e *UnsupportedError) Is(target error) bool {
+	return target == ErrValidation
+}
+
+// resolveModel returns the capabilities of the model a request to endpoint uses: the model
+// named by id, or the endpoint's default if id is empty. It reports false for models the
+// registry does not know, which are sent to the API unchecked, and fails for models that
+// are only known for other endpoints.
+func resolveModel(endpoint, id string) (ModelInfo, bool, error) {
+	if id == "" {
+		id = defaultModels[endpoint]
+	}
+	if info, ok := lookupModel(endpoint, id); ok {
+		return info, true, nil
+	}
+	if id != "" {
+		for _, info := range modelRegistry {
+			if info.ID == id {
+				return ModelInfo{}, false, &UnsupportedError{Endpoint: endpoint, Model: id, Parameter: "model", Value: id}
+			}
+		}
+	}
+	return ModelInfo{}, false, nil
+}
+
+// modelID returns the name of an optional model, or an empty string if it is nil.
+func modelID[M ~string](model *M) string {
+	if model == nil {
+		return ""
+	}
+	return string(*model)
+}
+
+// validateSpeechToTextModel checks the model and the optional language of a request to a
+// speech-to-text endpoint.
+func validateSpeechToTextModel(endpoint, id string, language *Language) error {
+	model, ok, err := resolveModel(endpoint, id)
+	if err != nil || !ok || language == nil {
+		return err
+	}
+	return model.validateLanguage("language_code", *language)
+}
+
+// unsupported returns the error for a value of parameter that m does not support.
+func (m ModelInfo) unsupported(parameter, value string, supported []string) error {
+	return &UnsupportedError{Endpoint: m.Endpoint, Model: m.ID, Parameter: parameter, Value: value, Supported: supported}
+}
+
+// validateInput checks input against the model's input limit.
+func (m ModelInfo) validateInput(input string) error {
+	if m.MaxInputLength > 0 {
+		return validateInputLength(input, m.MaxInputLength)
+	}
+	return nil
+}
+
+// validateLanguage checks that the model supports language, passed as parameter. Automatic
+// detection and an empty language are always accepted.
+func (m ModelInfo) validateLanguage(parameter string, language Language) error {
+	if language == "" || language == LanguageAuto || language == "unknown" || m.SupportsLanguage(language) {
+		return nil
+	}
+	supported := make([]string, len(m.Languages))
+	for i, l := range m.Languages {
+		supported[i] = string(l)
+	}
+	return m.unsupported(parameter, string(language), supported)
+}
+
+// validateSpeaker checks that the model supports speaker.
+func (m ModelInfo) validateSpeaker(speaker Speaker) error {
+	if m.SupportsSpeaker(speaker) {
+		return nil
+	}
+	supported := make([]string, len(m.Speakers))
+	for i, s := range m.Speakers {
+		supported[i] = string(s)
+	}
+	return m.unsupported("speaker", string(speaker), supported)
+}
+
+// validateSampleRate checks that the model supports the sample rate, passed as parameter.
+func (m ModelInfo) validateSampleRate(parameter string, sampleRate int) error {
+	if m.SupportsSampleRate(sampleRate) {
+		return nil
+	}
+	supported := make([]string, len(m.SampleRates))
+	for i, rate := range m.SampleRates {
+		supported[i] = fmt.Sprint(rate)
+	}
+	return m.unsupported(parameter, fmt.Sprint(sampleRate), supported)
+}